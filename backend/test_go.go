@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -196,7 +197,7 @@ func TestStats(t *testing.T) {
 
 func main() {
 	fmt.Println("🧪 Testing Go Backend...")
-	fmt.Println("=" * 60)
+	fmt.Println(strings.Repeat("=", 60))
 
 	// Wait for server to start
 	time.Sleep(2 * time.Second)
@@ -219,6 +220,6 @@ func main() {
 	fmt.Println("\n📊 Testing Statistics...")
 	TestStats(t)
 
-	fmt.Println("\n" + "="*60)
+	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Println("🏁 All tests completed!")
 }
@@ -0,0 +1,1226 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"pitipaw-backend/internal/metrics"
+	"pitipaw-backend/internal/ratelimit"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/image/draw"
+	"golang.org/x/time/rate"
+)
+
+// Database
+var db *mongo.Database
+
+// logger adalah structured logger (JSON, satu baris per event) yang
+// menggantikan log.Println/fmt.Println ad-hoc untuk hal-hal operasional
+// (koneksi DB, request per-route) di prototype stdlib ini.
+var logger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+// credentialLimiter membatasi percobaan login/register per IP+username;
+// uploadLimiter membatasi frekuensi dan volume upload gambar per admin.
+// TTL disamakan dengan jendela kuota masing-masing.
+var credentialLimiter = ratelimit.NewMemoryStore(10 * time.Minute)
+var uploadLimiter = ratelimit.NewMemoryStore(time.Hour)
+
+// uploadQuotaWindow dan uploadQuotaBytes adalah kuota kumulatif upload
+// gambar per admin (200MB/jam).
+const uploadQuotaWindow = time.Hour
+const uploadQuotaBytes = 200 * 1024 * 1024
+
+// Models
+type Product struct {
+	ID          primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Name        string             `json:"name" bson:"name"`
+	Price       float64            `json:"price" bson:"price"`
+	Description string             `json:"description" bson:"description"`
+	ImageURL    string             `json:"image_url" bson:"image_url"`
+}
+
+type Admin struct {
+	ID       primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Username string             `json:"username" bson:"username"`
+	Password string             `json:"password,omitempty" bson:"password"`
+}
+
+type ProductInput struct {
+	Name        string  `json:"name"`
+	Price       float64 `json:"price"`
+	Description string  `json:"description"`
+	ImageURL    string  `json:"image_url"`
+}
+
+type AdminInput struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type LoginInput struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type Response struct {
+	Status   string      `json:"status,omitempty"`
+	Message  string      `json:"message,omitempty"`
+	Error    string      `json:"error,omitempty"`
+	Data     interface{} `json:"data,omitempty"`
+	Database string      `json:"database,omitempty"`
+}
+
+// Constants
+const (
+	MaxFileSize = 10 * 1024 * 1024 // 10MB
+	UploadDir   = "static/uploads"
+
+	// maxImageDimension adalah sisi terpanjang gambar yang diizinkan sebelum
+	// di-decode penuh (mencegah decompression bomb: file kecil yang mengaku
+	// berdimensi raksasa lalu membengkak jadi gigabyte saat di-decode) dan
+	// sekaligus sisi terpanjang hasil resize.
+	maxImageDimension = 2048
+)
+
+// Auth
+const authCookieName = "auth"
+const jwtExpiry = 24 * time.Hour
+
+type contextKey string
+
+const adminContextKey contextKey = "admin"
+
+func jwtSecret() []byte {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		secret = "insecure-dev-secret-change-me"
+	}
+	return []byte(secret)
+}
+
+// authClaims mendefinisikan klaim JWT yang disisipkan pada token sesi admin.
+type authClaims struct {
+	Username string `json:"username"`
+	jwt.RegisteredClaims
+}
+
+// signAuthToken membuat JWT HS256 untuk admin yang berhasil login, berlaku 24 jam.
+func signAuthToken(admin Admin) (string, error) {
+	now := time.Now()
+	claims := authClaims{
+		Username: admin.Username,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   admin.ID.Hex(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(jwtExpiry)),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(jwtSecret())
+}
+
+// setAuthCookie menyetel cookie HttpOnly berisi JWT agar klien browser tidak
+// perlu menyimpan token secara manual di localStorage.
+func setAuthCookie(w http.ResponseWriter, token string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     authCookieName,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		MaxAge:   int(jwtExpiry.Seconds()),
+	})
+}
+
+// extractToken mengambil JWT dari header Authorization: Bearer ... atau cookie auth.
+func extractToken(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		return strings.TrimPrefix(header, "Bearer ")
+	}
+	if cookie, err := r.Cookie(authCookieName); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// requireAuth memverifikasi JWT pada request, memuat admin terkait, dan
+// menuliskan 401 sendiri jika token tidak ada/tidak valid. Dipanggil di awal
+// setiap handler yang memutasi data (create/update/delete).
+func requireAuth(w http.ResponseWriter, r *http.Request) (*Admin, bool) {
+	tokenString := extractToken(r)
+	if tokenString == "" {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return nil, false
+	}
+
+	claims := &authClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return jwtSecret(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		writeError(w, http.StatusUnauthorized, "Invalid or expired token")
+		return nil, false
+	}
+
+	objID, err := primitive.ObjectIDFromHex(claims.Subject)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "Invalid token subject")
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var admin Admin
+	if err := db.Collection("admins").FindOne(ctx, bson.M{"_id": objID}).Decode(&admin); err != nil {
+		writeError(w, http.StatusUnauthorized, "Admin not found")
+		return nil, false
+	}
+	admin.Password = ""
+
+	*r = *r.WithContext(contextWithAdmin(r.Context(), &admin))
+	return &admin, true
+}
+
+func contextWithAdmin(ctx context.Context, admin *Admin) context.Context {
+	return context.WithValue(ctx, adminContextKey, admin)
+}
+
+// credentialKey menggabungkan IP klien dan username yang dicoba, supaya
+// brute force dari satu IP terhadap banyak username (atau sebaliknya)
+// sama-sama kena limit.
+func credentialKey(r *http.Request, username string) string {
+	return r.RemoteAddr + "|" + username
+}
+
+// checkCredentialRateLimit membatasi login/register ke 5 percobaan/menit per
+// credentialKey, dan menolak key yang sedang kena lockout eksponensial
+// akibat kegagalan beruntun (lihat ratelimit.MemoryStore.RecordFailure).
+// Menulis 429 + Retry-After sendiri dan mengembalikan false jika ditolak.
+func checkCredentialRateLimit(w http.ResponseWriter, r *http.Request, username string) bool {
+	key := credentialKey(r, username)
+
+	if lockedUntil, locked := credentialLimiter.LockedUntil(key); locked {
+		writeRateLimited(w, time.Until(lockedUntil))
+		return false
+	}
+	if !credentialLimiter.Allow(key, rate.Every(12*time.Second), 5) {
+		writeRateLimited(w, 12*time.Second)
+		return false
+	}
+	return true
+}
+
+// writeRateLimited menulis response 429 dengan header Retry-After.
+func writeRateLimited(w http.ResponseWriter, retryAfter time.Duration) {
+	if retryAfter < time.Second {
+		retryAfter = time.Second
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+	writeError(w, http.StatusTooManyRequests, "Too many requests, please try again later")
+}
+
+// getAdminFromCtx mengembalikan admin yang sudah diautentikasi requireAuth,
+// dipakai handler untuk keperluan audit logging.
+func getAdminFromCtx(r *http.Request) (*Admin, bool) {
+	admin, ok := r.Context().Value(adminContextKey).(*Admin)
+	return admin, ok
+}
+
+func main() {
+	// Initialize MongoDB
+	initMongoDB()
+
+	// Create upload directory
+	os.MkdirAll(UploadDir, 0755)
+
+	// Setup HTTP routes
+	mux := http.NewServeMux()
+
+	// CORS middleware wrapper
+	corsHandler := func(h http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			setCORSHeaders(w)
+			if r.Method == "OPTIONS" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			h(w, r)
+		}
+	}
+
+	// API Routes. withMetrics dipasang paling luar (sebelum CORS) dengan label
+	// pola rute (bukan r.URL.Path mentah) supaya ID produk/admin di path tidak
+	// memecah metrik menjadi satu time series per ID.
+	mux.HandleFunc("/api/health", withMetrics("/api/health", corsHandler(healthCheck)))
+	mux.HandleFunc("/api/products", withMetrics("/api/products", corsHandler(productsHandler)))
+	mux.HandleFunc("/api/products/", withMetrics("/api/products/:id", corsHandler(productHandler)))
+	mux.HandleFunc("/api/admins", withMetrics("/api/admins", corsHandler(adminsHandler)))
+	mux.HandleFunc("/api/admins/", withMetrics("/api/admins/:id", corsHandler(adminHandler)))
+	mux.HandleFunc("/api/register", withMetrics("/api/register", corsHandler(registerHandler)))
+	mux.HandleFunc("/api/login", withMetrics("/api/login", corsHandler(loginHandler)))
+	mux.HandleFunc("/api/refresh", withMetrics("/api/refresh", corsHandler(refreshHandler)))
+	mux.HandleFunc("/api/logout", withMetrics("/api/logout", corsHandler(logoutHandler)))
+	mux.HandleFunc("/api/upload", withMetrics("/api/upload", corsHandler(uploadHandler)))
+	mux.HandleFunc("/api/stats", withMetrics("/api/stats", corsHandler(statsHandler)))
+
+	// Static files
+	mux.HandleFunc("/static/", corsHandler(staticHandler))
+
+	// Metrics endpoint, diekspos tanpa CORS karena hanya discrape Prometheus internal.
+	mux.Handle("/metrics", metrics.Handler())
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "5000"
+	}
+
+	fmt.Println("🚀 Pure Go Backend Starting...")
+	fmt.Printf("📊 Database: mongodb://localhost:27017/pitipaw\n")
+	fmt.Printf("📁 Upload folder: %s\n", UploadDir)
+	fmt.Println("🌐 CORS enabled for frontend")
+	fmt.Println("📋 Available endpoints:")
+	fmt.Println("   GET  /api/health")
+	fmt.Println("   GET  /api/products")
+	fmt.Println("   POST /api/products")
+	fmt.Println("   GET  /api/products/<id>")
+	fmt.Println("   PUT  /api/products/<id>")
+	fmt.Println("   DELETE /api/products/<id>")
+	fmt.Println("   GET  /api/admins")
+	fmt.Println("   POST /api/admins")
+	fmt.Println("   DELETE /api/admins/<id>")
+	fmt.Println("   GET  /api/login")
+	fmt.Println("   POST /api/login")
+	fmt.Println("   POST /api/refresh")
+	fmt.Println("   POST /api/logout")
+	fmt.Println("   POST /api/register")
+	fmt.Println("   POST /api/upload")
+	fmt.Println("   GET  /api/stats")
+
+	logger.Error("server stopped", "error", http.ListenAndServe(":"+port, mux))
+	os.Exit(1)
+}
+
+// requestIDHeader adalah nama header request/response yang membawa request ID,
+// sama dengan yang dipakai middleware.RequestID di dunia Gin.
+const requestIDHeader = "X-Request-ID"
+
+// statusCapturingWriter membungkus http.ResponseWriter agar status code dan
+// jumlah byte yang ditulis handler bisa dibaca kembali oleh withMetrics
+// setelah h selesai.
+type statusCapturingWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *statusCapturingWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusCapturingWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// withMetrics membungkus h dengan X-Request-ID (dibuat jika belum ada, lalu
+// digemakan di response header), mencatat satu baris log terstruktur per
+// request (method, route, status, latency, bytes, remote IP, request ID),
+// dan mengirim metrik method/route/status/latency ke Prometheus. label adalah
+// pola rute (bukan r.URL.Path mentah) supaya ID produk/admin di path tidak
+// memecah metrik menjadi satu time series per ID.
+func withMetrics(label string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(requestIDHeader)
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		w.Header().Set(requestIDHeader, requestID)
+
+		metrics.ActiveConnections.Inc()
+		defer metrics.ActiveConnections.Dec()
+
+		start := time.Now()
+		sw := &statusCapturingWriter{ResponseWriter: w, status: http.StatusOK}
+		h(sw, r)
+		elapsed := time.Since(start)
+
+		logger.Info("request",
+			"request_id", requestID,
+			"method", r.Method,
+			"route", label,
+			"status", sw.status,
+			"latency_ms", elapsed.Milliseconds(),
+			"bytes", sw.bytes,
+			"remote_ip", r.RemoteAddr,
+		)
+		metrics.Observe(label, r.Method, sw.status, elapsed)
+	}
+}
+
+func setCORSHeaders(w http.ResponseWriter) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, Response{Error: message})
+}
+
+func initMongoDB() {
+	mongoURI := os.Getenv("MONGO_URI")
+	if mongoURI == "" {
+		mongoURI = "mongodb://localhost:27017/pitipaw"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
+	if err != nil {
+		logger.Error("failed to connect to MongoDB", "error", err)
+		os.Exit(1)
+	}
+
+	// Test connection
+	err = client.Ping(ctx, nil)
+	if err != nil {
+		logger.Error("failed to ping MongoDB", "error", err)
+		os.Exit(1)
+	}
+
+	db = client.Database("pitipaw")
+	logger.Info("connected to MongoDB")
+
+	ensureProductIndexes(ctx)
+	ensureAdminIndexes(ctx)
+}
+
+// ensureAdminIndexes membuat unique index pada admins.username sehingga username
+// dobel ditolak oleh MongoDB sendiri, menggantikan pengecekan FindOne-lalu-Insert
+// yang rawan race condition (TOCTOU) saat dua request mendaftar dengan username
+// yang sama secara bersamaan.
+func ensureAdminIndexes(ctx context.Context) {
+	collection := db.Collection("admins")
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "username", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		logger.Warn("failed to ensure admin indexes", "error", err)
+	}
+}
+
+// ensureProductIndexes membuat text index pada name+description (dipakai getProducts
+// untuk pencarian ?q=) dan index pada price (dipakai untuk sort/filter rentang harga).
+func ensureProductIndexes(ctx context.Context) {
+	collection := db.Collection("products")
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "name", Value: "text"}, {Key: "description", Value: "text"}},
+		},
+		{
+			Keys: bson.D{{Key: "price", Value: 1}},
+		},
+	})
+	if err != nil {
+		logger.Warn("failed to ensure product indexes", "error", err)
+	}
+}
+
+// Health Check
+func healthCheck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := db.RunCommand(ctx, bson.D{{"ping", 1}}).Err()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, Response{
+			Status:  "error",
+			Message: "Database connection failed",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, Response{
+		Status:   "ok",
+		Message:  "Backend is running",
+		Database: "connected",
+	})
+}
+
+// Products handler
+func productsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		getProducts(w, r)
+	case "POST":
+		if _, ok := requireAuth(w, r); !ok {
+			return
+		}
+		createProduct(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func getProducts(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := r.URL.Query()
+
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	perPage, err := strconv.Atoi(query.Get("per_page"))
+	if err != nil || perPage < 1 {
+		perPage = 10
+	}
+	if perPage > 100 {
+		perPage = 100
+	}
+
+	filter := bson.M{}
+
+	priceFilter := bson.M{}
+	if minPrice := query.Get("min_price"); minPrice != "" {
+		if v, err := strconv.ParseFloat(minPrice, 64); err == nil {
+			priceFilter["$gte"] = v
+		}
+	}
+	if maxPrice := query.Get("max_price"); maxPrice != "" {
+		if v, err := strconv.ParseFloat(maxPrice, 64); err == nil {
+			priceFilter["$lte"] = v
+		}
+	}
+	if len(priceFilter) > 0 {
+		filter["price"] = priceFilter
+	}
+
+	if q := query.Get("q"); q != "" {
+		// regexp.QuoteMeta mencegah q dipakai sebagai pola regex (mis. nested
+		// quantifier yang memicu catastrophic backtracking di matcher MongoDB)
+		// -- dicocokkan sebagai teks literal, case-insensitive.
+		escaped := regexp.QuoteMeta(q)
+		filter["$or"] = []bson.M{
+			{"name": bson.M{"$regex": escaped, "$options": "i"}},
+			{"description": bson.M{"$regex": escaped, "$options": "i"}},
+		}
+	}
+
+	sortField := "_id"
+	sortOrder := 1
+	if sort := query.Get("sort"); sort != "" {
+		if strings.HasPrefix(sort, "-") {
+			sortField = strings.TrimPrefix(sort, "-")
+			sortOrder = -1
+		} else {
+			sortField = sort
+			sortOrder = 1
+		}
+	}
+
+	collection := db.Collection("products")
+
+	var total int64
+	err = metrics.TimeMongoOp("products", "count", func() error {
+		var e error
+		total, e = collection.CountDocuments(ctx, filter)
+		return e
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error counting products: "+err.Error())
+		return
+	}
+
+	findOptions := options.Find().
+		SetSkip(int64((page - 1) * perPage)).
+		SetLimit(int64(perPage)).
+		SetSort(bson.D{{Key: sortField, Value: sortOrder}})
+
+	var cursor *mongo.Cursor
+	err = metrics.TimeMongoOp("products", "find", func() error {
+		var e error
+		cursor, e = collection.Find(ctx, filter, findOptions)
+		return e
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error fetching products: "+err.Error())
+		return
+	}
+
+	var products []Product
+	if err = cursor.All(ctx, &products); err != nil {
+		writeError(w, http.StatusInternalServerError, "Error parsing products: "+err.Error())
+		return
+	}
+
+	totalPages := int((total + int64(perPage) - 1) / int64(perPage))
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":        products,
+		"page":        page,
+		"per_page":    perPage,
+		"total":       total,
+		"total_pages": totalPages,
+	})
+}
+
+func createProduct(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var input ProductInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	if input.Name == "" || input.Price <= 0 {
+		writeError(w, http.StatusBadRequest, "Nama dan harga produk wajib diisi")
+		return
+	}
+
+	product := Product{
+		Name:        input.Name,
+		Price:       input.Price,
+		Description: input.Description,
+		ImageURL:    input.ImageURL,
+	}
+
+	collection := db.Collection("products")
+	result, err := collection.InsertOne(ctx, product)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error creating product: "+err.Error())
+		return
+	}
+
+	product.ID = result.InsertedID.(primitive.ObjectID)
+	writeJSON(w, http.StatusCreated, product)
+}
+
+// Product handler (with ID)
+func productHandler(w http.ResponseWriter, r *http.Request) {
+	// Extract ID from URL path
+	path := strings.TrimPrefix(r.URL.Path, "/api/products/")
+	if path == "" {
+		writeError(w, http.StatusBadRequest, "Product ID required")
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		getProduct(w, r, path)
+	case "PUT":
+		if _, ok := requireAuth(w, r); !ok {
+			return
+		}
+		updateProduct(w, r, path)
+	case "DELETE":
+		if _, ok := requireAuth(w, r); !ok {
+			return
+		}
+		deleteProduct(w, r, path)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func getProduct(w http.ResponseWriter, r *http.Request, id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	collection := db.Collection("products")
+	var product Product
+	err = collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&product)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			writeError(w, http.StatusNotFound, "Produk tidak ditemukan")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "Error fetching product: "+err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, product)
+}
+
+func updateProduct(w http.ResponseWriter, r *http.Request, id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	var input map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	if len(input) == 0 {
+		writeError(w, http.StatusBadRequest, "Tidak ada data untuk diupdate")
+		return
+	}
+
+	collection := db.Collection("products")
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": objID}, bson.M{"$set": input})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error updating product: "+err.Error())
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		writeError(w, http.StatusNotFound, "Produk tidak ditemukan")
+		return
+	}
+
+	var product Product
+	err = collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&product)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error fetching updated product: "+err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, product)
+}
+
+func deleteProduct(w http.ResponseWriter, r *http.Request, id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	collection := db.Collection("products")
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": objID})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error deleting product: "+err.Error())
+		return
+	}
+
+	if result.DeletedCount == 0 {
+		writeError(w, http.StatusNotFound, "Produk tidak ditemukan")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "Produk berhasil dihapus"})
+}
+
+// Admins handler
+func adminsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		if _, ok := requireAuth(w, r); !ok {
+			return
+		}
+		getAdmins(w, r)
+	case "POST":
+		if _, ok := requireAuth(w, r); !ok {
+			return
+		}
+		createAdmin(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func getAdmins(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := db.Collection("admins")
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"password": 0}))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error fetching admins: "+err.Error())
+		return
+	}
+
+	var admins []Admin
+	if err = cursor.All(ctx, &admins); err != nil {
+		writeError(w, http.StatusInternalServerError, "Error parsing admins: "+err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, admins)
+}
+
+func createAdmin(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var input AdminInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	if input.Username == "" || input.Password == "" {
+		writeError(w, http.StatusBadRequest, "Username dan password wajib diisi")
+		return
+	}
+
+	if !checkCredentialRateLimit(w, r, input.Username) {
+		return
+	}
+
+	collection := db.Collection("admins")
+
+	// Hash password
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error hashing password")
+		return
+	}
+
+	admin := Admin{
+		Username: input.Username,
+		Password: string(hashedPassword),
+	}
+
+	result, err := collection.InsertOne(ctx, admin)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			writeError(w, http.StatusBadRequest, "Username sudah ada")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "Error creating admin: "+err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"_id":      result.InsertedID,
+		"username": admin.Username,
+		"message":  "Admin created successfully",
+	})
+}
+
+// Admin handler (with ID)
+func adminHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if _, ok := requireAuth(w, r); !ok {
+		return
+	}
+
+	// Extract ID from URL path
+	path := strings.TrimPrefix(r.URL.Path, "/api/admins/")
+	if path == "" {
+		writeError(w, http.StatusBadRequest, "Admin ID required")
+		return
+	}
+
+	deleteAdmin(w, r, path)
+}
+
+func deleteAdmin(w http.ResponseWriter, r *http.Request, id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid admin ID")
+		return
+	}
+
+	collection := db.Collection("admins")
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": objID})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error deleting admin: "+err.Error())
+		return
+	}
+
+	if result.DeletedCount == 0 {
+		writeError(w, http.StatusNotFound, "Admin tidak ditemukan")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "Admin berhasil dihapus"})
+}
+
+// Register handler
+func registerHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	createAdmin(w, r)
+}
+
+// Login handler
+func loginHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"message": "Login endpoint ready",
+			"methods": []string{"POST"},
+		})
+	case "POST":
+		loginAdmin(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func loginAdmin(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var input LoginInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	if input.Username == "" || input.Password == "" {
+		writeError(w, http.StatusBadRequest, "Username dan password wajib diisi")
+		return
+	}
+
+	if !checkCredentialRateLimit(w, r, input.Username) {
+		return
+	}
+
+	collection := db.Collection("admins")
+	var admin Admin
+	err := collection.FindOne(ctx, bson.M{"username": input.Username}).Decode(&admin)
+	if err != nil {
+		credentialLimiter.RecordFailure(credentialKey(r, input.Username))
+		writeError(w, http.StatusUnauthorized, "Username/password salah")
+		return
+	}
+
+	// Check password
+	err = bcrypt.CompareHashAndPassword([]byte(admin.Password), []byte(input.Password))
+	if err != nil {
+		credentialLimiter.RecordFailure(credentialKey(r, input.Username))
+		writeError(w, http.StatusUnauthorized, "Username/password salah")
+		return
+	}
+	credentialLimiter.ResetFailures(credentialKey(r, input.Username))
+
+	token, err := signAuthToken(admin)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to generate token")
+		return
+	}
+	setAuthCookie(w, token)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Login berhasil",
+		"token":   token,
+		"admin":   map[string]string{"username": admin.Username},
+	})
+}
+
+// refreshHandler menerbitkan JWT baru selama token lama (yang sudah
+// diverifikasi requireAuth) masih berlaku, tanpa meminta login ulang.
+func refreshHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	admin, ok := requireAuth(w, r)
+	if !ok {
+		return
+	}
+
+	token, err := signAuthToken(*admin)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to generate token")
+		return
+	}
+	setAuthCookie(w, token)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"token": token})
+}
+
+// logoutHandler menghapus cookie sesi. Karena JWT ini stateless, token yang
+// sudah terlanjur dipegang klien lain (mis. Bearer header) tetap valid sampai
+// expired; ini hanya membersihkan sesi berbasis cookie pada browser ini.
+func logoutHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     authCookieName,
+		Value:    "",
+		Path:     "/",
+		HttpOnly: true,
+		MaxAge:   -1,
+	})
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "Logout berhasil"})
+}
+
+// Upload handler
+func uploadHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	admin, ok := requireAuth(w, r)
+	if !ok {
+		return
+	}
+
+	if !uploadLimiter.Allow(admin.ID.Hex(), rate.Every(3*time.Second), 20) {
+		writeRateLimited(w, 3*time.Second)
+		return
+	}
+	if r.ContentLength > 0 {
+		used := uploadLimiter.AddBytes(admin.ID.Hex(), r.ContentLength, uploadQuotaWindow)
+		if used > uploadQuotaBytes {
+			writeRateLimited(w, uploadQuotaWindow)
+			return
+		}
+	}
+
+	// Parse multipart form
+	err := r.ParseMultipartForm(MaxFileSize)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "File terlalu besar. Maksimal 10MB")
+		return
+	}
+
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "No file part")
+		return
+	}
+	defer file.Close()
+
+	// Check file size
+	if header.Size > MaxFileSize {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("File terlalu besar. Maksimal 10MB (ukuran file: %.1fMB)", float64(header.Size)/(1024*1024)))
+		return
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(file, MaxFileSize+1))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error reading file: "+err.Error())
+		return
+	}
+	if len(raw) > MaxFileSize {
+		writeError(w, http.StatusBadRequest, "File terlalu besar. Maksimal 10MB")
+		return
+	}
+
+	// Sniff the actual content, ignoring the client-supplied filename/extension,
+	// dan decode+re-encode lewat codec stdlib sehingga metadata (mis. EXIF) yang
+	// menempel pada file asli tidak ikut tersimpan.
+	contentType := http.DetectContentType(raw)
+	var decode func(io.Reader) (image.Image, error)
+	var decodeConfig func(io.Reader) (image.Config, error)
+	var ext string
+	switch contentType {
+	case "image/jpeg":
+		decode, decodeConfig, ext = jpeg.Decode, jpeg.DecodeConfig, "jpg"
+	case "image/png":
+		decode, decodeConfig, ext = png.Decode, png.DecodeConfig, "png"
+	case "image/gif":
+		decode, decodeConfig, ext = gif.Decode, gif.DecodeConfig, "gif"
+	default:
+		writeError(w, http.StatusBadRequest, "File not allowed")
+		return
+	}
+
+	// Cek dimensi klaim lewat header file saja (murah) sebelum decode penuh,
+	// supaya file kecil yang mengaku berdimensi raksasa tidak membengkak jadi
+	// gigabyte di memori saat di-decode (decompression bomb).
+	cfg, err := decodeConfig(bytes.NewReader(raw))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "File is not a valid image")
+		return
+	}
+	if cfg.Width > 8192 || cfg.Height > 8192 {
+		writeError(w, http.StatusBadRequest, "Image dimensions too large")
+		return
+	}
+
+	img, err := decode(bytes.NewReader(raw))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "File is not a valid image")
+		return
+	}
+
+	// Resize ke maxImageDimension pada sisi terpanjang, menjaga aspect ratio;
+	// gambar yang sudah lebih kecil dibiarkan apa adanya.
+	if b := img.Bounds(); b.Dx() > maxImageDimension || b.Dy() > maxImageDimension {
+		scale := float64(maxImageDimension) / float64(b.Dx())
+		if s := float64(maxImageDimension) / float64(b.Dy()); s < scale {
+			scale = s
+		}
+		newWidth := int(float64(b.Dx()) * scale)
+		newHeight := int(float64(b.Dy()) * scale)
+		resized := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
+		draw.CatmullRom.Scale(resized, resized.Bounds(), img, b, draw.Over, nil)
+		img = resized
+	}
+
+	var reencoded bytes.Buffer
+	switch ext {
+	case "jpg":
+		err = jpeg.Encode(&reencoded, img, &jpeg.Options{Quality: 85})
+	case "png":
+		err = png.Encode(&reencoded, img)
+	case "gif":
+		err = gif.Encode(&reencoded, img, nil)
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error re-encoding image: "+err.Error())
+		return
+	}
+
+	// Nama file diturunkan dari hash konten (bukan dari header.Filename klien),
+	// sehingga path traversal lewat nama file tidak mungkin terjadi dan upload
+	// dengan konten identik otomatis terdeduplikasi ke file yang sama.
+	hash := sha256.Sum256(reencoded.Bytes())
+	hashHex := hex.EncodeToString(hash[:])
+	now := time.Now()
+	relativeDir := fmt.Sprintf("%d/%02d", now.Year(), now.Month())
+	dir := filepath.Join(UploadDir, relativeDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		writeError(w, http.StatusInternalServerError, "Error creating upload directory: "+err.Error())
+		return
+	}
+
+	filename := hashHex + "." + ext
+	destPath := filepath.Join(dir, filename)
+
+	if _, err := os.Stat(destPath); os.IsNotExist(err) {
+		if err := os.WriteFile(destPath, reencoded.Bytes(), 0o644); err != nil {
+			writeError(w, http.StatusInternalServerError, "Error saving file: "+err.Error())
+			return
+		}
+	}
+
+	metrics.UploadsTotal.Inc()
+
+	writeJSON(w, http.StatusCreated, map[string]string{
+		"image_url": fmt.Sprintf("/static/uploads/%s/%s", relativeDir, filename),
+		"file_size": fmt.Sprintf("%.1fMB", float64(len(reencoded.Bytes()))/(1024*1024)),
+		"hash":      hashHex,
+	})
+}
+
+// Stats handler
+func statsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	productsCount, err := db.Collection("products").CountDocuments(ctx, bson.M{})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Stats error: "+err.Error())
+		return
+	}
+
+	adminsCount, err := db.Collection("admins").CountDocuments(ctx, bson.M{})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Stats error: "+err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"total_products": productsCount,
+		"total_admins":   adminsCount,
+		"status":         "ok",
+	})
+}
+
+// Static files handler
+func staticHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Extract filename from URL
+	path := strings.TrimPrefix(r.URL.Path, "/static/")
+	if path == "" {
+		writeError(w, http.StatusBadRequest, "File not found")
+		return
+	}
+
+	// Security: prevent directory traversal
+	if strings.Contains(path, "..") {
+		writeError(w, http.StatusBadRequest, "Invalid file path")
+		return
+	}
+
+	fullPath := filepath.Join("static", path)
+	
+	// Check if file exists
+	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+		writeError(w, http.StatusNotFound, "File not found")
+		return
+	}
+
+	// Serve file
+	http.ServeFile(w, r, fullPath)
+}
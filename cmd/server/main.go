@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"pitipaw-backend/config"
+	"pitipaw-backend/controllers"
+	"pitipaw-backend/internal/cron"
+	"pitipaw-backend/internal/password"
+	"pitipaw-backend/internal/ratelimit"
+	"pitipaw-backend/middleware"
+	"pitipaw-backend/routes"
+	"time"
+
+	"github.com/cloudinary/cloudinary-go/v2"
+	"go.uber.org/zap"
+)
+
+func main() {
+	// 0. Mode seeding superadmin: -create-admin membaca SEED_ADMIN_* dari env,
+	// meng-upsert akun superadmin, lalu keluar tanpa menjalankan server --
+	// berguna untuk menyiapkan akun pertama saat deploy tanpa lewat /register.
+	createAdmin := flag.Bool("create-admin", false, "seed a superadmin account from SEED_ADMIN_* env vars and exit")
+	flag.Parse()
+
+	if *createAdmin {
+		cfg := config.NewManager().Current()
+		dbClient, err := config.ConnectDB(cfg.MongoURI, cfg.MongoMode)
+		if err != nil {
+			log.Fatalf("Could not connect to database: %v", err)
+		}
+		defer dbClient.Disconnect(context.Background())
+
+		db := dbClient.Database("pitipaw")
+		if err := controllers.SeedSuperAdmin(context.Background(), db); err != nil {
+			log.Fatalf("Failed to seed superadmin: %v", err)
+		}
+		fmt.Println("✅ Superadmin account seeded successfully")
+		return
+	}
+
+	// 1. Muat Konfigurasi
+	cfgManager := config.NewManager()
+	cfgManager.WatchSIGHUP()
+	cfg := cfgManager.Current()
+
+	var logger *zap.Logger
+	var zapErr error
+	if cfg.Env == "production" {
+		logger, zapErr = zap.NewProduction()
+	} else {
+		logger, zapErr = zap.NewDevelopment()
+	}
+	if zapErr != nil {
+		log.Fatalf("Could not initialize logger: %v", zapErr)
+	}
+	defer logger.Sync()
+
+	// 2. Hubungkan ke Database
+	dbClient, err := config.ConnectDB(cfg.MongoURI, cfg.MongoMode)
+	if err != nil {
+		log.Fatalf("Could not connect to database: %v", err)
+	}
+	defer func() {
+		if err := dbClient.Disconnect(context.Background()); err != nil {
+			log.Printf("Error disconnecting from MongoDB: %v", err)
+		}
+	}()
+	db := dbClient.Database("pitipaw")
+
+	if err := middleware.EnsureIdempotencyIndexes(context.Background(), db); err != nil {
+		log.Printf("Warning: failed to ensure idempotency indexes: %v", err)
+	}
+
+	if err := config.EnsureIndexes(context.Background(), db); err != nil {
+		log.Printf("Warning: failed to ensure indexes: %v", err)
+	}
+
+	if err := controllers.EnsureAuthIndexes(context.Background(), db); err != nil {
+		log.Printf("Warning: failed to ensure auth indexes: %v", err)
+	}
+
+	if err := controllers.EnsureMFAIndexes(context.Background(), db); err != nil {
+		log.Printf("Warning: failed to ensure MFA indexes: %v", err)
+	}
+
+	// 3. Inisialisasi Cloudinary
+	var cld *cloudinary.Cloudinary
+	if cfg.CloudinaryURL != "" {
+		cld, err = cloudinary.NewFromURL(cfg.CloudinaryURL)
+		if err != nil {
+			log.Fatalf("Failed to initialize Cloudinary: %v", err)
+		}
+		fmt.Println("☁️  Successfully connected to Cloudinary")
+	}
+
+	// 4. Inisialisasi Controller dengan dependensi
+	ctrl := &controllers.Controller{
+		DB:              db,
+		Cld:             cld,
+		PasetoSecretKey: cfg.PasetoSecretKey,
+		Logger:          logger,
+		Config:          cfgManager,
+		AccountLockout:  ratelimit.NewMemoryStoreWithLockout(cfg.LoginWindow, cfg.LoginMaxAttempts, cfg.LoginLockout, time.Hour),
+		PasswordPolicy:  password.DefaultPolicy(),
+		BreachChecker:   password.NewBreachChecker(nil),
+	}
+
+	// 4b. Jalankan job reconciliation asset Cloudinary setiap jam
+	if cld != nil {
+		reconciler := cron.NewAssetReconciler(db, cld, "pitipaw/products")
+		reconciler.Start(context.Background(), time.Hour)
+		ctrl.Reconciler = reconciler
+	}
+
+	// 5. Atur Rute
+	r := routes.Setup(ctrl, cfg.Env)
+
+	// 6. Jalankan Server
+	fmt.Printf("🚀 Server starting on port %s\n", cfg.Port)
+	fmt.Printf("💡 API available at http://localhost:%s/api\n", cfg.Port)
+	
+	if err := r.Run(":" + cfg.Port); err != nil {
+		log.Fatal("Error starting server:", err)
+	}
+}
\ No newline at end of file
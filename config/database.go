@@ -6,6 +6,7 @@ import (
 	// "log"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -31,4 +32,34 @@ func ConnectDB(uri string, mode string) (*mongo.Client, error) {
 	}
 
 	return client, nil
+}
+
+// EnsureIndexes membuat index unik/sparse yang menjadi sumber kebenaran untuk
+// constraint yang sebelumnya hanya dicek lewat FindOne-before-insert (rawan
+// race condition/TOCTOU saat dua request mendaftar dengan username yang sama
+// secara bersamaan).
+func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	admins := db.Collection("admins")
+	if _, err := admins.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "username", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+	}); err != nil {
+		return fmt.Errorf("error ensuring admin indexes: %w", err)
+	}
+
+	products := db.Collection("products")
+	if _, err := products.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	}); err != nil {
+		return fmt.Errorf("error ensuring product indexes: %w", err)
+	}
+
+	return nil
 }
\ No newline at end of file
@@ -1,33 +1,115 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
-// AppConfig menampung semua variabel konfigurasi aplikasi.
+// AppConfig menampung semua variabel konfigurasi aplikasi. Nilainya diisi
+// lapis demi lapis oleh Load: default bawaan, lalu config.json/config.yaml
+// jika ada, lalu environment variable (urutan ini juga yang menentukan siapa
+// yang menang jika sebuah key muncul di lebih dari satu lapis).
 type AppConfig struct {
 	Port            string
 	Env             string
 	MongoMode       string
 	MongoURI        string
+	DBName          string
+	UploadDir       string
+	MaxFileSize     int64
+	JWTSecret       string
+	AllowedOrigins  []string
 	PasetoSecretKey []byte
 	CloudinaryURL   string
+
+	// LoginMaxAttempts, LoginWindow, dan LoginLockout mengatur lockout akun
+	// login: LoginMaxAttempts kegagalan beruntun dalam LoginWindow memicu
+	// lockout selama LoginLockout (berlipat dua tiap kegagalan tambahan,
+	// lihat internal/ratelimit.MemoryStore).
+	LoginMaxAttempts int
+	LoginWindow      time.Duration
+	LoginLockout     time.Duration
+
+	// PasswordBreachCheck mengaktifkan pengecekan password baru terhadap
+	// database HIBP (k-anonymity) saat Register/ChangePassword. Dimatikan
+	// secara bawaan karena butuh akses jaringan keluar yang mungkin tidak
+	// tersedia di semua lingkungan deploy.
+	PasswordBreachCheck bool
+}
+
+// fileConfig merepresentasikan skema config.json/config.yaml, opsional dan
+// hanya dipakai untuk mengisi nilai yang belum di-set lewat environment.
+type fileConfig struct {
+	Port           string   `json:"port" yaml:"port"`
+	Env            string   `json:"env" yaml:"env"`
+	MongoURI       string   `json:"mongo_uri" yaml:"mongo_uri"`
+	DBName         string   `json:"db_name" yaml:"db_name"`
+	UploadDir      string   `json:"upload_dir" yaml:"upload_dir"`
+	MaxFileSize    int64    `json:"max_file_size" yaml:"max_file_size"`
+	JWTSecret      string   `json:"jwt_secret" yaml:"jwt_secret"`
+	AllowedOrigins []string `json:"allowed_origins" yaml:"allowed_origins"`
+}
+
+// configFilePaths adalah lokasi yang dicoba Load secara berurutan; yang
+// pertama ditemukan dipakai.
+var configFilePaths = []string{"config.json", "config.yaml", "config.yml"}
+
+func loadFileConfig() fileConfig {
+	for _, path := range configFilePaths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var fc fileConfig
+		if strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml") {
+			err = yaml.Unmarshal(data, &fc)
+		} else {
+			err = json.Unmarshal(data, &fc)
+		}
+		if err != nil {
+			log.Printf("Warning: failed to parse %s: %v", path, err)
+			continue
+		}
+		return fc
+	}
+	return fileConfig{}
 }
 
-// Load memuat konfigurasi dari file .env atau environment variables.
+// Load memuat konfigurasi dari file .env, config.json/config.yaml, lalu
+// environment variables (yang terakhir selalu menang).
 func Load() *AppConfig {
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using environment variables")
 	}
 
+	fc := loadFileConfig()
+
 	cfg := &AppConfig{
-		Port:          getEnv("PORT", "5000"),
-		Env:           getEnv("ENVIRONMENT", "development"),
-		MongoMode:     getEnv("MONGO_MODE", "local"),
-		CloudinaryURL: getEnv("CLOUDINARY_URL", ""),
+		Port:           getEnv("PORT", orDefault(fc.Port, "5000")),
+		Env:            getEnv("ENVIRONMENT", orDefault(fc.Env, "development")),
+		MongoMode:      getEnv("MONGO_MODE", "local"),
+		DBName:         getEnv("DB_NAME", orDefault(fc.DBName, "pitipaw")),
+		UploadDir:      getEnv("UPLOAD_DIR", orDefault(fc.UploadDir, "static/uploads")),
+		MaxFileSize:    getEnvInt64("MAX_FILE_SIZE", orDefaultInt64(fc.MaxFileSize, 10*1024*1024)),
+		JWTSecret:      getEnv("JWT_SECRET", orDefault(fc.JWTSecret, "insecure-dev-secret-change-me")),
+		AllowedOrigins: getEnvList("ALLOWED_ORIGINS", orDefaultList(fc.AllowedOrigins, []string{"http://localhost:3000", "http://127.0.0.1:3000", "http://localhost:8000"})),
+		CloudinaryURL:  getEnv("CLOUDINARY_URL", ""),
+
+		LoginMaxAttempts: int(getEnvInt64("LOGIN_MAX_ATTEMPTS", 5)),
+		LoginWindow:      getEnvDuration("LOGIN_WINDOW", 10*time.Minute),
+		LoginLockout:     getEnvDuration("LOGIN_LOCKOUT", 30*time.Second),
+
+		PasswordBreachCheck: getEnvBool("PASSWORD_BREACH_CHECK", false),
 	}
 
 	// Atur URI MongoDB berdasarkan mode
@@ -37,7 +119,7 @@ func Load() *AppConfig {
 			log.Fatal("MONGO_MODE 'atlas' but MONGO_URI_ATLAS is not set")
 		}
 	} else {
-		cfg.MongoURI = getEnv("MONGO_URI_LOCAL", "mongodb://localhost:27017/pitipaw")
+		cfg.MongoURI = getEnv("MONGO_URI_LOCAL", orDefault(fc.MongoURI, "mongodb://localhost:27017/pitipaw"))
 	}
 
 	// Atur Kunci Paseto
@@ -50,9 +132,102 @@ func Load() *AppConfig {
 	return cfg
 }
 
+// Fingerprint mengembalikan hash ringkas dari isi konfigurasi, dipakai Manager
+// untuk mendeteksi apakah sebuah reload benar-benar mengubah nilai sebelum
+// menyebarkan config baru ke seluruh aplikasi.
+func (c *AppConfig) Fingerprint() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		c.Port, c.Env, c.MongoMode, c.MongoURI, c.DBName, c.UploadDir,
+		strconv.FormatInt(c.MaxFileSize, 10), c.JWTSecret,
+		strings.Join(c.AllowedOrigins, ","), c.CloudinaryURL,
+		string(c.PasetoSecretKey),
+		strconv.Itoa(c.LoginMaxAttempts), c.LoginWindow.String(), c.LoginLockout.String(),
+		strconv.FormatBool(c.PasswordBreachCheck),
+	}, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Redacted mengembalikan salinan config dengan field rahasia dikosongkan,
+// aman untuk diekspos lewat GET /api/config.
+func (c *AppConfig) Redacted() *AppConfig {
+	redacted := *c
+	redacted.JWTSecret = ""
+	redacted.PasetoSecretKey = nil
+	return &redacted
+}
+
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+func getEnvInt64(key string, defaultValue int64) int64 {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return parsed
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return defaultValue
+	}
+	return parsed
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return defaultValue
+	}
+	return parsed
+}
+
+func getEnvList(key string, defaultValue []string) []string {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return defaultValue
+	}
+	parts := strings.Split(value, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+func orDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func orDefaultInt64(value, defaultValue int64) int64 {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
+
+func orDefaultList(value, defaultValue []string) []string {
+	if len(value) == 0 {
+		return defaultValue
+	}
+	return value
+}
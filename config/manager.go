@@ -0,0 +1,67 @@
+// File: config/manager.go
+package config
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// Manager menyimpan AppConfig yang sedang aktif di belakang sync.RWMutex
+// sehingga bisa ditukar secara atomik saat reload, tanpa request yang sedang
+// berjalan membaca config dalam keadaan setengah-update.
+type Manager struct {
+	mu      sync.RWMutex
+	current *AppConfig
+}
+
+// NewManager memuat konfigurasi awal lewat Load dan mengembalikan Manager
+// yang siap dipakai.
+func NewManager() *Manager {
+	return &Manager{current: Load()}
+}
+
+// Current mengembalikan snapshot config yang sedang aktif.
+func (m *Manager) Current() *AppConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.current
+}
+
+// Reload membaca ulang .env/config.json/config.yaml/environment lewat Load
+// dan menukar config aktif hanya jika fingerprint-nya benar-benar berubah,
+// supaya reload yang tidak mengubah apa pun tidak memicu log/efek samping
+// yang tidak perlu.
+func (m *Manager) Reload() (changed bool, fingerprint string) {
+	next := Load()
+	nextFingerprint := next.Fingerprint()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current != nil && m.current.Fingerprint() == nextFingerprint {
+		return false, nextFingerprint
+	}
+	m.current = next
+	return true, nextFingerprint
+}
+
+// WatchSIGHUP memulai goroutine yang memanggil Reload setiap proses menerima
+// SIGHUP (mis. lewat `kill -HUP <pid>`), meniru pola reload konfigurasi ala
+// nginx/systemd tanpa perlu dependency file-watcher tambahan.
+func (m *Manager) WatchSIGHUP() {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	go func() {
+		for range sighup {
+			changed, fingerprint := m.Reload()
+			if changed {
+				log.Printf("config reloaded via SIGHUP (fingerprint=%s)", fingerprint)
+			} else {
+				log.Println("config reload via SIGHUP: no changes detected")
+			}
+		}
+	}()
+}
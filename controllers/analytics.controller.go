@@ -0,0 +1,177 @@
+// File: controllers/analytics.controller.go
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// CategoryStat merangkum jumlah dan total stok produk per kategori.
+type CategoryStat struct {
+	Category      string  `json:"category" bson:"_id"`
+	TotalProducts int64   `json:"total_products" bson:"total_products"`
+	TotalStock    int64   `json:"total_stock" bson:"total_stock"`
+	TotalValue    float64 `json:"total_value" bson:"total_value"`
+}
+
+// GetStatsByCategory mengelompokkan produk berdasarkan kategori.
+func (ctrl *Controller) GetStatsByCategory(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pipeline := bson.A{
+		bson.M{"$group": bson.M{
+			"_id":            "$category",
+			"total_products": bson.M{"$sum": 1},
+			"total_stock":    bson.M{"$sum": "$stock"},
+			"total_value":    bson.M{"$sum": bson.M{"$multiply": []interface{}{"$price", "$stock"}}},
+		}},
+		bson.M{"$sort": bson.M{"_id": 1}},
+	}
+
+	cursor, err := ctrl.DB.Collection("products").Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	results := []CategoryStat{}
+	if err := cursor.All(ctx, &results); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"by_category": results})
+}
+
+// GetLowStockProducts mengembalikan produk dengan stok di bawah ambang batas.
+func (ctrl *Controller) GetLowStockProducts(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	threshold, err := strconv.Atoi(c.Query("threshold"))
+	if err != nil || threshold < 0 {
+		threshold = 5
+	}
+
+	cursor, err := ctrl.DB.Collection("products").Find(ctx, bson.M{"stock": bson.M{"$lte": threshold}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	results := []bson.M{}
+	if err := cursor.All(ctx, &results); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"threshold": threshold, "low_stock": results})
+}
+
+// PriceBucket merangkum satu rentang harga dari $bucketAuto.
+type PriceBucket struct {
+	ID struct {
+		Min float64 `json:"min" bson:"min"`
+		Max float64 `json:"max" bson:"max"`
+	} `json:"range" bson:"_id"`
+	Count int64 `json:"count" bson:"count"`
+}
+
+// GetPriceHistogram membagi harga produk ke dalam sejumlah bucket otomatis.
+func (ctrl *Controller) GetPriceHistogram(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	buckets, err := strconv.Atoi(c.Query("buckets"))
+	if err != nil || buckets < 1 {
+		buckets = 10
+	}
+
+	pipeline := bson.A{
+		bson.M{"$bucketAuto": bson.M{
+			"groupBy": "$price",
+			"buckets": buckets,
+			"output": bson.M{
+				"count": bson.M{"$sum": 1},
+			},
+		}},
+	}
+
+	cursor, err := ctrl.DB.Collection("products").Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	results := []bson.M{}
+	if err := cursor.All(ctx, &results); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"buckets": buckets, "histogram": results})
+}
+
+var allowedTimeseriesFields = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+}
+
+var allowedTimeseriesIntervals = map[string]string{
+	"day":   "day",
+	"week":  "week",
+	"month": "month",
+	"year":  "year",
+}
+
+// GetTimeseries mengelompokkan produk berdasarkan rentang waktu (mis. harian)
+// pada field tanggal tertentu, cocok untuk ditampilkan sebagai grafik tren.
+func (ctrl *Controller) GetTimeseries(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	field := c.Query("field")
+	if !allowedTimeseriesFields[field] {
+		field = "created_at"
+	}
+
+	interval, ok := allowedTimeseriesIntervals[c.Query("interval")]
+	if !ok {
+		interval = "day"
+	}
+
+	pipeline := bson.A{
+		bson.M{"$group": bson.M{
+			"_id": bson.M{"$dateTrunc": bson.M{
+				"date": "$" + field,
+				"unit": interval,
+			}},
+			"count": bson.M{"$sum": 1},
+		}},
+		bson.M{"$sort": bson.M{"_id": 1}},
+	}
+
+	cursor, err := ctrl.DB.Collection("products").Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	results := []bson.M{}
+	if err := cursor.All(ctx, &results); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"field": field, "interval": interval, "timeseries": results})
+}
@@ -2,19 +2,178 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"os"
+	"pitipaw-backend/middleware"
 	"pitipaw-backend/models"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/o1egl/paseto"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// accessTokenTTL adalah masa berlaku access token PASETO. Dipersingkat dari
+// 24 jam menjadi 15 menit karena pencabutannya sekarang ditopang refresh
+// token yang dirotasi lewat RefreshToken; token curian tidak lagi berumur
+// panjang sebelum refreshTokenTTL.
+const accessTokenTTL = 15 * time.Minute
 
-// Login menangani proses login admin.
+// refreshTokenTTL adalah masa berlaku refresh token yang disimpan di koleksi
+// refresh_tokens.
+const refreshTokenTTL = 7 * 24 * time.Hour
+
+// EnsureAuthIndexes membuat index yang dipakai subsistem refresh token:
+// token_hash unik di refresh_tokens, dan TTL di kedua koleksi agar token
+// yang sudah kedaluwarsa otomatis terbuang tanpa job pembersihan terpisah.
+func EnsureAuthIndexes(ctx context.Context, db *mongo.Database) error {
+	refreshTokens := db.Collection("refresh_tokens")
+	if _, err := refreshTokens.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}); err != nil {
+		return err
+	}
+
+	revokedAccessTokens := db.Collection("revoked_access_tokens")
+	if _, err := revokedAccessTokens.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}); err != nil {
+		return err
+	}
+
+	roles := db.Collection("roles")
+	if _, err := roles.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		return err
+	}
+
+	return seedDefaultRoles(ctx, roles)
+}
+
+// seedDefaultRoles meng-upsert role bawaan yang dipakai RBAC: superadmin
+// (wildcard, lihat middleware.TokenFooter.HasPermission) dan admin (operasi
+// sehari-hari, tanpa akses mengelola admin lain). $setOnInsert dipakai supaya
+// panggilan ini aman dijalankan berulang setiap kali server start tanpa
+// menimpa perubahan permission yang sudah dikustomisasi operator.
+func seedDefaultRoles(ctx context.Context, roles *mongo.Collection) error {
+	defaults := []models.Role{
+		{Name: "superadmin", Permissions: []string{"*"}},
+		{Name: "admin", Permissions: []string{"products:create", "products:update", "products:delete", "admins:read"}},
+	}
+	for _, role := range defaults {
+		_, err := roles.UpdateOne(ctx, bson.M{"name": role.Name}, bson.M{"$setOnInsert": role}, options.Update().SetUpsert(true))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolvePermissions mengambil daftar permission untuk sebuah role dari
+// koleksi roles. Role yang tidak ditemukan dianggap tidak punya permission
+// sama sekali (bukan error), supaya token yang diterbitkan untuk role yang
+// belum terdaftar tetap valid tapi dibatasi RequirePermission di semua rute.
+func resolvePermissions(ctx context.Context, db *mongo.Database, roleName string) []string {
+	var role models.Role
+	if err := db.Collection("roles").FindOne(ctx, bson.M{"name": roleName}).Decode(&role); err != nil {
+		return nil
+	}
+	return role.Permissions
+}
+
+// issueAccessToken menerbitkan PASETO access token berumur pendek dengan Jti
+// unik, supaya token ini bisa dicabut sewaktu-waktu lewat
+// revoked_access_tokens tanpa menunggu masa berlakunya habis. Role dan
+// permission admin saat ini disematkan di footer (JSON, lihat
+// middleware.TokenFooter) supaya RequirePermission bisa memeriksanya tanpa
+// query DB tambahan per request.
+func issueAccessToken(ctx context.Context, ctrl *Controller, admin *models.Admin) (string, error) {
+	now := time.Now()
+	jsonToken := paseto.JSONToken{
+		Subject:    admin.ID.Hex(),
+		Jti:        uuid.NewString(),
+		IssuedAt:   now,
+		Expiration: now.Add(accessTokenTTL),
+	}
+	footer := middleware.TokenFooter{
+		Role:        admin.Role,
+		Permissions: resolvePermissions(ctx, ctrl.DB, admin.Role),
+	}
+	footerBytes, err := json.Marshal(footer)
+	if err != nil {
+		return "", err
+	}
+	return paseto.NewV2().Encrypt(ctrl.PasetoSecretKey, jsonToken, string(footerBytes))
+}
+
+// issueRefreshToken membuat refresh token baru: nilai mentah dikembalikan ke
+// pemanggil untuk dikirim ke klien, sedangkan yang disimpan di refresh_tokens
+// hanya hash SHA-256-nya.
+func issueRefreshToken(ctx context.Context, ctrl *Controller, adminID primitive.ObjectID, userAgent string) (string, error) {
+	raw := uuid.NewString()
+	hash := sha256.Sum256([]byte(raw))
+
+	record := models.RefreshToken{
+		TokenHash: hex.EncodeToString(hash[:]),
+		AdminID:   adminID,
+		UserAgent: userAgent,
+		Revoked:   false,
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	}
+	if _, err := ctrl.DB.Collection("refresh_tokens").InsertOne(ctx, record); err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// validatePassword menjalankan ctrl.PasswordPolicy terhadap candidate, lalu
+// menambahkan pengecekan kebocoran HIBP jika Config.Current().PasswordBreachCheck
+// aktif dan ctrl.BreachChecker tersedia. Kegagalan memanggil HIBP (mis. jaringan
+// tidak tersedia) tidak menolak password -- hanya dicatat dan dilewati, supaya
+// registrasi/ganti password tidak gagal total akibat layanan pihak ketiga.
+func (ctrl *Controller) validatePassword(ctx context.Context, candidate, username, email string) []string {
+	errs := ctrl.PasswordPolicy.Validate(candidate, username, email)
+
+	if ctrl.Config != nil && ctrl.Config.Current().PasswordBreachCheck && ctrl.BreachChecker != nil {
+		pwned, err := ctrl.BreachChecker.IsPwned(ctx, candidate)
+		if err != nil {
+			if ctrl.Logger != nil {
+				ctrl.Logger.Warn("HIBP breach check failed", zap.Error(err))
+			}
+		} else if pwned {
+			errs = append(errs, "password has appeared in a known data breach, please choose a different one")
+		}
+	}
+
+	return errs
+}
+
+// Login menangani proses login admin. Selain CredentialRateLimit (per
+// IP+username, lihat middleware/ratelimit.go), akun juga dikunci lewat
+// ctrl.AccountLockout yang dikunci per username saja -- supaya penyerang
+// yang berganti-ganti IP tidak bisa menghindari lockout, dan supaya
+// /api/admins/:id/unlock bisa membuka lockout tanpa tahu IP penyerang.
 func (ctrl *Controller) Login(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -25,37 +184,171 @@ func (ctrl *Controller) Login(c *gin.Context) {
 		return
 	}
 
+	if ctrl.AccountLockout != nil {
+		if lockedUntil, locked := ctrl.AccountLockout.LockedUntil(req.Username); locked {
+			retryAfter := time.Until(lockedUntil)
+			c.Header("Retry-After", fmt.Sprintf("%d", int(retryAfter.Seconds())))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Account locked due to too many failed login attempts"})
+			return
+		}
+	}
+
 	var admin models.Admin
 	collection := ctrl.DB.Collection("admins")
 	err := collection.FindOne(ctx, bson.M{"username": req.Username}).Decode(&admin)
 	if err != nil {
+		if ctrl.AccountLockout != nil {
+			ctrl.AccountLockout.RecordFailure(req.Username)
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
 	if bcrypt.CompareHashAndPassword([]byte(admin.Password), []byte(req.Password)) != nil {
+		if ctrl.AccountLockout != nil {
+			ctrl.AccountLockout.RecordFailure(req.Username)
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
-	now := time.Now()
-	exp := now.Add(24 * time.Hour)
-	jsonToken := paseto.JSONToken{
-		Subject:    admin.ID.Hex(),
-		IssuedAt:   now,
-		Expiration: exp,
+	if ctrl.AccountLockout != nil {
+		ctrl.AccountLockout.ResetFailures(req.Username)
 	}
-	token, err := paseto.NewV2().Encrypt(ctrl.PasetoSecretKey, jsonToken, "pitipaw-admin")
+
+	if admin.MFAEnabled {
+		mfaToken, err := issueMFAChallenge(ctx, ctrl.DB, admin.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start MFA challenge"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"mfa_required": true, "mfa_token": mfaToken})
+		return
+	}
+
+	token, err := issueAccessToken(ctx, ctrl, &admin)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
 
+	refreshToken, err := issueRefreshToken(ctx, ctrl, admin.ID, c.Request.UserAgent())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate refresh token"})
+		return
+	}
+
 	admin.Password = ""
-	c.JSON(http.StatusOK, gin.H{"message": "Login successful", "admin": admin, "token": token})
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Login successful",
+		"admin":         admin,
+		"token":         token,
+		"refresh_token": refreshToken,
+	})
 }
 
-// Register menangani registrasi admin baru.
+// RefreshToken memvalidasi refresh token yang dikirim klien, merotasinya
+// (menandai yang lama revoked dan menerbitkan yang baru), lalu mengembalikan
+// access token PASETO yang baru. Rotasi mencegah refresh token yang sama
+// dipakai berulang kali jika bocor.
+func (ctrl *Controller) RefreshToken(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hash := sha256.Sum256([]byte(req.RefreshToken))
+	tokenHash := hex.EncodeToString(hash[:])
+
+	collection := ctrl.DB.Collection("refresh_tokens")
+	var existing models.RefreshToken
+	if err := collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&existing); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	if existing.Revoked || time.Now().After(existing.ExpiresAt) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token expired or revoked"})
+		return
+	}
+
+	if _, err := collection.UpdateOne(ctx, bson.M{"_id": existing.ID}, bson.M{"$set": bson.M{"revoked": true}}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rotate refresh token"})
+		return
+	}
+
+	newRefreshToken, err := issueRefreshToken(ctx, ctrl, existing.AdminID, c.Request.UserAgent())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue refresh token"})
+		return
+	}
+
+	var admin models.Admin
+	if err := ctrl.DB.Collection("admins").FindOne(ctx, bson.M{"_id": existing.AdminID}).Decode(&admin); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin not found"})
+		return
+	}
+
+	accessToken, err := issueAccessToken(ctx, ctrl, &admin)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": accessToken, "refresh_token": newRefreshToken})
+}
+
+// Logout mencabut refresh token yang dikirim klien (atau seluruh refresh
+// token admin jika All true), dan menambahkan jti access token yang sedang
+// dipakai ke revoked_access_tokens supaya token yang baru saja dipakai
+// langsung tidak valid tanpa menunggu masa berlakunya habis.
+func (ctrl *Controller) Logout(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var req models.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hash := sha256.Sum256([]byte(req.RefreshToken))
+	tokenHash := hex.EncodeToString(hash[:])
+
+	collection := ctrl.DB.Collection("refresh_tokens")
+	var existing models.RefreshToken
+	if err := collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&existing); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	filter := bson.M{"_id": existing.ID}
+	if req.All {
+		filter = bson.M{"admin_id": existing.AdminID}
+	}
+	if _, err := collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"revoked": true}}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke refresh token"})
+		return
+	}
+
+	if claims, ok := middleware.GetAccessClaimsFromCtx(c); ok && claims.Jti != "" {
+		_, _ = ctrl.DB.Collection("revoked_access_tokens").InsertOne(ctx, bson.M{
+			"jti":        claims.Jti,
+			"expires_at": claims.Expiration,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// Register menangani registrasi admin baru. Admin pertama di sistem otomatis
+// di-bootstrap sebagai superadmin tanpa perlu token (supaya sistem yang masih
+// kosong bisa dimulai); setelah itu, registrasi berikutnya wajib memakai
+// token superadmin yang valid, dan admin baru dibuat dengan role "admin".
 func (ctrl *Controller) Register(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -67,9 +360,29 @@ func (ctrl *Controller) Register(c *gin.Context) {
 	}
 
 	collection := ctrl.DB.Collection("admins")
-	var existingAdmin models.Admin
-	if err := collection.FindOne(ctx, bson.M{"username": req.Username}).Decode(&existingAdmin); err == nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "Username already exists"})
+
+	count, err := collection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	role := "admin"
+	if count == 0 {
+		role = "superadmin"
+	} else {
+		if !middleware.Authenticate(c, ctrl.DB, ctrl.PasetoSecretKey) {
+			return
+		}
+		actingAdmin, _ := middleware.GetAdminFromCtx(c)
+		if actingAdmin == nil || actingAdmin.Role != "superadmin" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only a superadmin can register new admins"})
+			return
+		}
+	}
+
+	if errs := ctrl.validatePassword(c.Request.Context(), req.Password, req.Username, req.Email); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errs})
 		return
 	}
 
@@ -83,11 +396,16 @@ func (ctrl *Controller) Register(c *gin.Context) {
 		Username:  req.Username,
 		Email:     req.Email,
 		Password:  string(hashedPassword),
+		Role:      role,
 		CreatedAt: time.Now(),
 	}
 
 	result, err := collection.InsertOne(ctx, admin)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Username already exists"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -149,6 +467,137 @@ func (ctrl *Controller) DeleteAdmin(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Admin deleted successfully"})
 }
 
+// UnlockAdmin membuka lockout login akun admin (POST /api/admins/:id/unlock).
+// Dipasang di belakang RequirePermission("admins:unlock") di routes.Setup,
+// sehingga hanya superadmin (atau role dengan permission tersebut) yang bisa
+// memanggilnya.
+func (ctrl *Controller) UnlockAdmin(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	id := c.Param("id")
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid admin ID"})
+		return
+	}
+
+	var admin models.Admin
+	if err := ctrl.DB.Collection("admins").FindOne(ctx, bson.M{"_id": objectID}).Decode(&admin); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Admin not found"})
+		return
+	}
+
+	if ctrl.AccountLockout != nil {
+		ctrl.AccountLockout.ResetFailures(admin.Username)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account unlocked successfully"})
+}
+
+// UpdateAdmin menangani perubahan username/email admin (PATCH /api/admins/:id).
+// Digate oleh RequirePasswordConfirmation supaya token yang bocor tidak bisa
+// mengubah identitas akun admin lain tanpa tahu password admin yang sedang login.
+func (ctrl *Controller) UpdateAdmin(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	id := c.Param("id")
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid admin ID"})
+		return
+	}
+
+	var req models.UpdateAdminRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	update := bson.M{}
+	if req.Username != "" {
+		update["username"] = req.Username
+	}
+	if req.Email != "" {
+		update["email"] = req.Email
+	}
+	if len(update) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+
+	collection := ctrl.DB.Collection("admins")
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": update})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Username already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Admin not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Admin updated successfully"})
+}
+
+// ChangePassword menangani penggantian password admin (PATCH /api/admins/:id/password).
+// Digate oleh RequirePasswordConfirmation menggunakan password admin yang
+// sedang login, bukan password target :id, karena yang diverifikasi adalah
+// identitas pelaku, bukan pemilik akun yang diubah.
+func (ctrl *Controller) ChangePassword(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	id := c.Param("id")
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid admin ID"})
+		return
+	}
+
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	collection := ctrl.DB.Collection("admins")
+
+	var target models.Admin
+	if err := collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&target); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Admin not found"})
+		return
+	}
+
+	if errs := ctrl.validatePassword(ctx, req.NewPassword, target.Username, target.Email); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errs})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		return
+	}
+
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": bson.M{"password": string(hashedPassword)}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Admin not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
+}
+
 // CreateAdmin menangani pembuatan admin baru.
 // Fungsi ini mungkin tidak diperlukan jika Anda menggunakan endpoint /register.
 func (ctrl *Controller) CreateAdmin(c *gin.Context) {
@@ -164,11 +613,18 @@ func (ctrl *Controller) CreateAdmin(c *gin.Context) {
 	// Anda harus menambahkan hashing password di sini seperti pada fungsi Register
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(admin.Password), bcrypt.DefaultCost)
 	admin.Password = string(hashedPassword)
+	if admin.Role == "" {
+		admin.Role = "admin"
+	}
 	admin.CreatedAt = time.Now()
 
 	collection := ctrl.DB.Collection("admins")
 	result, err := collection.InsertOne(ctx, admin)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Username already exists"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -176,4 +632,35 @@ func (ctrl *Controller) CreateAdmin(c *gin.Context) {
 	admin.ID = result.InsertedID.(primitive.ObjectID)
 	admin.Password = ""
 	c.JSON(http.StatusCreated, gin.H{"admin": admin})
+}
+
+// SeedSuperAdmin meng-upsert akun superadmin dari variabel lingkungan
+// SEED_ADMIN_USERNAME/SEED_ADMIN_EMAIL/SEED_ADMIN_PASSWORD, dipakai flag CLI
+// -create-admin di main.go untuk menyiapkan akun pertama tanpa lewat endpoint
+// /register (mis. saat deploy awal, sebelum ada admin sama sekali).
+func SeedSuperAdmin(ctx context.Context, db *mongo.Database) error {
+	username := os.Getenv("SEED_ADMIN_USERNAME")
+	email := os.Getenv("SEED_ADMIN_EMAIL")
+	password := os.Getenv("SEED_ADMIN_PASSWORD")
+	if username == "" || email == "" || password == "" {
+		return fmt.Errorf("SEED_ADMIN_USERNAME, SEED_ADMIN_EMAIL, and SEED_ADMIN_PASSWORD must all be set")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	collection := db.Collection("admins")
+	update := bson.M{
+		"$set": bson.M{
+			"username": username,
+			"email":    email,
+			"password": string(hashedPassword),
+			"role":     "superadmin",
+		},
+		"$setOnInsert": bson.M{"created_at": time.Now()},
+	}
+	_, err = collection.UpdateOne(ctx, bson.M{"username": username}, update, options.Update().SetUpsert(true))
+	return err
 }
\ No newline at end of file
@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetConfig mengembalikan konfigurasi aktif (admin-only, lewat field rahasia
+// yang sudah dikosongkan oleh AppConfig.Redacted) agar operator bisa
+// memverifikasi nilai apa yang sedang dipakai server tanpa membocorkan secret.
+func (ctrl *Controller) GetConfig(c *gin.Context) {
+	if ctrl.Config == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Config manager is not available"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"config": ctrl.Config.Current().Redacted()})
+}
+
+// ReloadConfig membaca ulang .env/config.json/config.yaml/environment dan
+// menukar config aktif jika ada perubahan nyata (dibandingkan lewat
+// Fingerprint), tanpa perlu mengirim SIGHUP ke proses.
+func (ctrl *Controller) ReloadConfig(c *gin.Context) {
+	if ctrl.Config == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Config manager is not available"})
+		return
+	}
+
+	changed, fingerprint := ctrl.Config.Reload()
+	c.JSON(http.StatusOK, gin.H{
+		"changed":     changed,
+		"fingerprint": fingerprint,
+		"config":      ctrl.Config.Current().Redacted(),
+	})
+}
@@ -0,0 +1,34 @@
+// File: controllers/controller.go
+package controllers
+
+import (
+	"pitipaw-backend/config"
+	"pitipaw-backend/internal/cron"
+	"pitipaw-backend/internal/password"
+	"pitipaw-backend/internal/ratelimit"
+
+	"github.com/cloudinary/cloudinary-go/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// Controller menampung dependensi yang akan digunakan oleh semua handler.
+// Pastikan field diawali huruf besar agar bisa diakses dari package lain.
+type Controller struct {
+	DB              *mongo.Database
+	Cld             *cloudinary.Cloudinary
+	PasetoSecretKey []byte
+	Reconciler      *cron.AssetReconciler
+	Logger          *zap.Logger
+	Config          *config.Manager
+	// AccountLockout menampung kegagalan login beruntun per username (terpisah
+	// dari credentialLimiter di routes.Setup yang mengunci per pasangan
+	// IP+username), supaya akun tetap bisa dikunci walau penyerang berganti IP,
+	// dan supaya /api/admins/:id/unlock bisa membuka lockout lewat username saja.
+	AccountLockout ratelimit.Store
+	// PasswordPolicy menentukan aturan kekuatan password untuk Register dan
+	// ChangePassword. BreachChecker opsional (nil-safe) dan hanya dipanggil
+	// jika Config.Current().PasswordBreachCheck true.
+	PasswordPolicy password.Policy
+	BreachChecker  *password.BreachChecker
+}
\ No newline at end of file
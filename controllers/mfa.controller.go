@@ -0,0 +1,271 @@
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"pitipaw-backend/internal/cryptoutil"
+	"pitipaw-backend/internal/totp"
+	"pitipaw-backend/middleware"
+	"pitipaw-backend/models"
+	"time"
+
+	qrcode "github.com/skip2/go-qrcode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// mfaChallengeTTL adalah masa berlaku mfa_token yang diterbitkan Login ketika
+// admin memiliki 2FA aktif; cukup singkat karena pengguna diharapkan langsung
+// memasukkan kode TOTP yang sedang tampil di authenticator-nya.
+const mfaChallengeTTL = 5 * time.Minute
+
+// mfaIssuer dipakai sebagai label issuer di otpauth URI/QR, muncul di
+// aplikasi authenticator sebagai nama penerbit akun.
+const mfaIssuer = "Pitipaw"
+
+// recoveryCodeCount adalah jumlah kode pemulihan yang diterbitkan saat 2FA
+// diaktifkan; masing-masing sekali pakai.
+const recoveryCodeCount = 8
+
+// EnsureMFAIndexes membuat TTL index pada mfa_challenges supaya challenge
+// yang sudah kedaluwarsa (tidak pernah diselesaikan lewat LoginVerify)
+// otomatis terbuang tanpa job pembersihan terpisah.
+func EnsureMFAIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("mfa_challenges").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	return err
+}
+
+// issueMFAChallenge menerbitkan mfa_token mentah untuk langkah kedua login;
+// hanya hash SHA-256-nya yang disimpan di mfa_challenges, mengikuti pola
+// issueRefreshToken.
+func issueMFAChallenge(ctx context.Context, db *mongo.Database, adminID primitive.ObjectID) (string, error) {
+	raw := uuid.NewString()
+	hash := sha256.Sum256([]byte(raw))
+
+	challenge := models.MFAChallenge{
+		TokenHash: hex.EncodeToString(hash[:]),
+		AdminID:   adminID,
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(mfaChallengeTTL),
+	}
+	if _, err := db.Collection("mfa_challenges").InsertOne(ctx, challenge); err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// LoginVerify menyelesaikan langkah kedua login untuk admin dengan 2FA
+// aktif: menukar mfa_token + kode TOTP (atau kode pemulihan) dengan access
+// dan refresh token PASETO yang sesungguhnya.
+func (ctrl *Controller) LoginVerify(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var req models.LoginVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hash := sha256.Sum256([]byte(req.MFAToken))
+	tokenHash := hex.EncodeToString(hash[:])
+
+	challenges := ctrl.DB.Collection("mfa_challenges")
+	var challenge models.MFAChallenge
+	if err := challenges.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&challenge); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired MFA token"})
+		return
+	}
+	if time.Now().After(challenge.ExpiresAt) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired MFA token"})
+		return
+	}
+
+	var admin models.Admin
+	if err := ctrl.DB.Collection("admins").FindOne(ctx, bson.M{"_id": challenge.AdminID}).Decode(&admin); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin not found"})
+		return
+	}
+
+	if !ctrl.verifyMFACode(ctx, &admin, req.Code) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid MFA code"})
+		return
+	}
+
+	// mfa_token hanya boleh dipakai sekali.
+	_, _ = challenges.DeleteOne(ctx, bson.M{"_id": challenge.ID})
+
+	token, err := issueAccessToken(ctx, ctrl, &admin)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+	refreshToken, err := issueRefreshToken(ctx, ctrl, admin.ID, c.Request.UserAgent())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate refresh token"})
+		return
+	}
+
+	admin.Password = ""
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Login successful",
+		"admin":         admin,
+		"token":         token,
+		"refresh_token": refreshToken,
+	})
+}
+
+// verifyMFACode mencocokkan code terhadap TOTP admin, dan jika tidak cocok,
+// terhadap daftar kode pemulihannya. Kode pemulihan yang terpakai langsung
+// dibuang dari admin.MFARecoveryCodes supaya tidak bisa dipakai ulang.
+func (ctrl *Controller) verifyMFACode(ctx context.Context, admin *models.Admin, code string) bool {
+	secret, err := cryptoutil.DecryptString(ctrl.PasetoSecretKey, admin.MFASecret)
+	if err == nil && totp.Verify(secret, code, time.Now()) {
+		return true
+	}
+
+	for i, hashed := range admin.MFARecoveryCodes {
+		if bcrypt.CompareHashAndPassword([]byte(hashed), []byte(code)) == nil {
+			remaining := append(admin.MFARecoveryCodes[:i:i], admin.MFARecoveryCodes[i+1:]...)
+			_, _ = ctrl.DB.Collection("admins").UpdateOne(ctx, bson.M{"_id": admin.ID}, bson.M{"$set": bson.M{"mfa_recovery_codes": remaining}})
+			admin.MFARecoveryCodes = remaining
+			return true
+		}
+	}
+	return false
+}
+
+// EnrollMFA memulai pendaftaran 2FA untuk admin yang sedang login: membuat
+// secret TOTP baru (disimpan terenkripsi, belum mengaktifkan 2FA) dan
+// mengembalikan otpauth URI beserta QR PNG (base64) untuk dipindai
+// authenticator. 2FA baru aktif setelah ConfirmMFA memverifikasi kode pertama.
+func (ctrl *Controller) EnrollMFA(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	admin, ok := middleware.GetAdminFromCtx(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate MFA secret"})
+		return
+	}
+
+	encryptedSecret, err := cryptoutil.EncryptString(ctrl.PasetoSecretKey, secret)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encrypt MFA secret"})
+		return
+	}
+
+	update := bson.M{"$set": bson.M{"mfa_secret": encryptedSecret, "mfa_enabled": false}}
+	if _, err := ctrl.DB.Collection("admins").UpdateOne(ctx, bson.M{"_id": admin.ID}, update); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store MFA secret"})
+		return
+	}
+
+	uri := totp.ProvisioningURI(mfaIssuer, admin.Username, secret)
+
+	png, err := qrcode.Encode(uri, qrcode.Medium, 256)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate QR code"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"otpauth_uri": uri,
+		"qr_png":      base64.StdEncoding.EncodeToString(png),
+	})
+}
+
+// ConfirmMFA memverifikasi kode pertama dari authenticator yang baru saja
+// dipasang lewat EnrollMFA, lalu mengaktifkan 2FA dan menerbitkan kode
+// pemulihan (ditampilkan sekali, disimpan sebagai hash bcrypt).
+func (ctrl *Controller) ConfirmMFA(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	admin, ok := middleware.GetAdminFromCtx(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var req models.MFAConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var current models.Admin
+	if err := ctrl.DB.Collection("admins").FindOne(ctx, bson.M{"_id": admin.ID}).Decode(&current); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Admin not found"})
+		return
+	}
+	if current.MFASecret == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "MFA enrollment has not been started"})
+		return
+	}
+
+	secret, err := cryptoutil.DecryptString(ctrl.PasetoSecretKey, current.MFASecret)
+	if err != nil || !totp.Verify(secret, req.Code, time.Now()) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid MFA code"})
+		return
+	}
+
+	recoveryCodes, hashedCodes, err := generateRecoveryCodes()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate recovery codes"})
+		return
+	}
+
+	update := bson.M{"$set": bson.M{"mfa_enabled": true, "mfa_recovery_codes": hashedCodes}}
+	if _, err := ctrl.DB.Collection("admins").UpdateOne(ctx, bson.M{"_id": admin.ID}, update); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enable MFA"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "MFA enabled successfully",
+		"recovery_codes": recoveryCodes,
+	})
+}
+
+// generateRecoveryCodes membuat recoveryCodeCount kode pemulihan acak (raw,
+// untuk ditampilkan sekali ke admin) beserta hash bcrypt-nya (untuk disimpan).
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashed := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		raw := make([]byte, 5)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+		code := hex.EncodeToString(raw)
+		hash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, nil, err
+		}
+		codes[i] = code
+		hashed[i] = string(hash)
+	}
+	return codes, hashed, nil
+}
+
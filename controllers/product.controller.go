@@ -5,35 +5,229 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"pitipaw-backend/internal/metrics"
+	"pitipaw-backend/middleware"
 	"pitipaw-backend/models"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/cloudinary/cloudinary-go/v2/api"
 	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 )
 
-// GetProducts menangani pengambilan semua produk.
+const (
+	defaultPage  = 1
+	defaultLimit = 10
+	maxLimit     = 100
+
+	productImageFolder   = "pitipaw/products"
+	maxConcurrentUploads = 4
+)
+
+// eagerTransformations mendeskripsikan varian turunan yang dibuat Cloudinary
+// secara asinkron setiap kali sebuah gambar produk diunggah, sehingga klien
+// bisa merender galeri responsif lewat srcset tanpa transformasi on-the-fly.
+const eagerTransformations = "c_thumb,w_200,h_200|c_limit,w_800,h_800|c_limit,w_800,h_800,f_webp"
+
+// uploadProductImages mengunggah sekumpulan payload base64 ke Cloudinary secara
+// paralel (dibatasi maxConcurrentUploads worker) dan mengembalikan hasilnya
+// dalam urutan yang sama dengan input.
+func uploadProductImages(ctrl *Controller, base64Images []string) ([]models.ProductImage, error) {
+	images := make([]models.ProductImage, len(base64Images))
+
+	g := new(errgroup.Group)
+	sem := make(chan struct{}, maxConcurrentUploads)
+
+	for i, payload := range base64Images {
+		i, payload := i, payload
+		sem <- struct{}{}
+		g.Go(func() error {
+			defer func() { <-sem }()
+
+			uploadResult, err := ctrl.Cld.Upload.Upload(
+				context.Background(),
+				payload,
+				uploader.UploadParams{
+					Folder:     productImageFolder,
+					EagerAsync: api.Bool(true),
+					Eager:      eagerTransformations,
+				},
+			)
+			if err != nil {
+				return err
+			}
+
+			thumbnail := uploadResult.SecureURL
+			if len(uploadResult.Eager) > 0 {
+				thumbnail = uploadResult.Eager[0].SecureURL
+			}
+
+			images[i] = models.ProductImage{
+				PublicID:  uploadResult.PublicID,
+				URL:       uploadResult.SecureURL,
+				Width:     uploadResult.Width,
+				Height:    uploadResult.Height,
+				Thumbnail: thumbnail,
+				IsPrimary: i == 0,
+			}
+			metrics.UploadsTotal.Inc()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// destroyProductImages menghapus galeri gambar sebuah produk dari Cloudinary
+// agar tidak ada asset yatim yang tertinggal.
+func destroyProductImages(ctrl *Controller, images []models.ProductImage) {
+	if ctrl.Cld == nil {
+		return
+	}
+	for _, img := range images {
+		if img.PublicID == "" {
+			continue
+		}
+		if _, err := ctrl.Cld.Upload.Destroy(context.Background(), uploader.DestroyParams{PublicID: img.PublicID}); err != nil {
+			log.Println("Cloudinary destroy error:", err)
+		}
+	}
+}
+
+// GetProducts menangani pengambilan produk dengan paginasi, filter, sort, dan pencarian teks.
 func (ctrl *Controller) GetProducts(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		page = defaultPage
+	}
+
+	limitParam := c.Query("per_page")
+	if limitParam == "" {
+		limitParam = c.Query("limit")
+	}
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil || limit < 1 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	filter := bson.M{}
+
+	if category := c.Query("category"); category != "" {
+		filter["category"] = category
+	}
+
+	priceFilter := bson.M{}
+	if minPrice := c.Query("min_price"); minPrice != "" {
+		if v, err := strconv.ParseFloat(minPrice, 64); err == nil {
+			priceFilter["$gte"] = v
+		}
+	}
+	if maxPrice := c.Query("max_price"); maxPrice != "" {
+		if v, err := strconv.ParseFloat(maxPrice, 64); err == nil {
+			priceFilter["$lte"] = v
+		}
+	}
+	if len(priceFilter) > 0 {
+		filter["price"] = priceFilter
+	}
+
+	if q := c.Query("q"); q != "" {
+		// regexp.QuoteMeta mencegah q dipakai sebagai pola regex (mis. nested
+		// quantifier yang memicu catastrophic backtracking di matcher MongoDB)
+		// -- dicocokkan sebagai teks literal, case-insensitive.
+		escaped := regexp.QuoteMeta(q)
+		filter["$or"] = []bson.M{
+			{"name": bson.M{"$regex": escaped, "$options": "i"}},
+			{"description": bson.M{"$regex": escaped, "$options": "i"}},
+		}
+	}
+
+	sortOrder := 1
+	if c.Query("sort_order") == "desc" {
+		sortOrder = -1
+	}
+	sortColumn := c.Query("sort_column")
+	if sortColumn == "" {
+		sortColumn = "created_at"
+	}
+
+	// sort mendukung konvensi ringkas "-field" (descending) sebagai alternatif
+	// pasangan sort_column/sort_order di atas, mis. ?sort=-price.
+	if s := c.Query("sort"); s != "" {
+		if strings.HasPrefix(s, "-") {
+			sortColumn = strings.TrimPrefix(s, "-")
+			sortOrder = -1
+		} else {
+			sortColumn = s
+			sortOrder = 1
+		}
+	}
+
+	sort := bson.D{{Key: sortColumn, Value: sortOrder}}
+
 	collection := ctrl.DB.Collection("products")
-	cursor, err := collection.Find(ctx, bson.M{})
+
+	var total int64
+	err = metrics.TimeMongoOp("products", "count", func() error {
+		var e error
+		total, e = collection.CountDocuments(ctx, filter)
+		return e
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	findOptions := options.Find().
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit)).
+		SetSort(sort)
+
+	var cursor *mongo.Cursor
+	err = metrics.TimeMongoOp("products", "find", func() error {
+		var e error
+		cursor, e = collection.Find(ctx, filter, findOptions)
+		return e
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 	defer cursor.Close(ctx)
 
-	var productList []models.Product
+	productList := []models.Product{}
 	if err = cursor.All(ctx, &productList); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"products": productList})
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"products":    productList,
+		"total":       total,
+		"page":        page,
+		"limit":       limit,
+		"total_pages": totalPages,
+	})
 }
 
 // CreateProduct menangani pembuatan produk baru.
@@ -47,27 +241,28 @@ func (ctrl *Controller) CreateProduct(c *gin.Context) {
 		return
 	}
 
-	if product.ImageBase64 != "" && ctrl.Cld != nil {
-		uploadResult, err := ctrl.Cld.Upload.Upload(
-			context.Background(),
-			product.ImageBase64,
-			uploader.UploadParams{Folder: "pitipaw/products"},
-		)
+	if len(product.ImagesBase64) > 0 && ctrl.Cld != nil {
+		images, err := uploadProductImages(ctrl, product.ImagesBase64)
 		if err != nil {
-			log.Println("Cloudinary upload error:", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+			ctrl.Logger.Error("cloudinary upload failed", zap.String("request_id", c.GetString(middleware.RequestIDKey)), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload images"})
 			return
 		}
-		product.ImageURL = uploadResult.SecureURL
-		product.Image = uploadResult.PublicID
+		product.Images = images
 	}
 
 	product.CreatedAt = time.Now()
 	product.UpdatedAt = time.Now()
-	product.ImageBase64 = ""
+	product.Version = 1
+	product.ImagesBase64 = nil
 
 	collection := ctrl.DB.Collection("products")
-	result, err := collection.InsertOne(ctx, product)
+	var result *mongo.InsertOneResult
+	err := metrics.TimeMongoOp("products", "insert", func() error {
+		var e error
+		result, e = collection.InsertOne(ctx, product)
+		return e
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -91,7 +286,9 @@ func (ctrl *Controller) GetProduct(c *gin.Context) {
 
 	var product models.Product
 	collection := ctrl.DB.Collection("products")
-	err = collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&product)
+	err = metrics.TimeMongoOp("products", "findOne", func() error {
+		return collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&product)
+	})
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
@@ -116,34 +313,130 @@ func (ctrl *Controller) UpdateProduct(c *gin.Context) {
 		return
 	}
 
+	collection := ctrl.DB.Collection("products")
+
+	var existing models.Product
+	err = metrics.TimeMongoOp("products", "findOne", func() error {
+		return collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&existing)
+	})
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	var updateData models.Product
 	if err := c.ShouldBindJSON(&updateData); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Jika ada gambar baru (imageBase64), upload ke Cloudinary
-	if updateData.ImageBase64 != "" && ctrl.Cld != nil {
-		uploadResult, err := ctrl.Cld.Upload.Upload(
-			context.Background(),
-			updateData.ImageBase64,
-			uploader.UploadParams{Folder: "pitipaw/products"},
-		)
+	droppedImages := existing.Images
+
+	// Jika ada gambar baru (imagesBase64), upload ke Cloudinary dan gantikan galeri lama.
+	if len(updateData.ImagesBase64) > 0 && ctrl.Cld != nil {
+		images, err := uploadProductImages(ctrl, updateData.ImagesBase64)
 		if err != nil {
-			log.Println("Cloudinary upload error:", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+			ctrl.Logger.Error("cloudinary upload failed", zap.String("request_id", c.GetString(middleware.RequestIDKey)), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload images"})
 			return
 		}
-		updateData.ImageURL = uploadResult.SecureURL
-		updateData.Image = uploadResult.PublicID
+		updateData.Images = images
+	} else {
+		updateData.Images = existing.Images
+		droppedImages = nil
 	}
 
 	updateData.UpdatedAt = time.Now()
-	updateData.ImageBase64 = ""
-	update := bson.M{"$set": updateData}
+	updateData.ImagesBase64 = nil
+
+	clientVersion := updateData.Version
+	update := bson.M{
+		"$set": bson.M{
+			"name":        updateData.Name,
+			"price":       updateData.Price,
+			"description": updateData.Description,
+			"category":    updateData.Category,
+			"images":      updateData.Images,
+			"stock":       updateData.Stock,
+			"updated_at":  updateData.UpdatedAt,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+
+	var result *mongo.UpdateResult
+	err = metrics.TimeMongoOp("products", "update", func() error {
+		var e error
+		result, e = collection.UpdateOne(ctx, bson.M{"_id": objectID, "version": clientVersion}, update)
+		return e
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Product was modified by someone else, please refresh and try again"})
+		return
+	}
+
+	destroyProductImages(ctrl, droppedImages)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product updated successfully"})
+}
+
+var mutableProductFields = map[string]bool{
+	"name":        true,
+	"price":       true,
+	"description": true,
+	"category":    true,
+	"stock":       true,
+}
+
+// PatchProduct menerapkan update parsial: hanya field yang dikirim klien yang
+// ditulis, field lain yang tidak disebut tetap tidak tersentuh.
+func (ctrl *Controller) PatchProduct(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	id := c.Param("id")
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var fields map[string]interface{}
+	if err := c.ShouldBindJSON(&fields); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	set := bson.M{}
+	for key, value := range fields {
+		if mutableProductFields[key] {
+			set[key] = value
+		}
+	}
+	if len(set) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No valid fields to update"})
+		return
+	}
+	set["updated_at"] = time.Now()
 
 	collection := ctrl.DB.Collection("products")
-	result, err := collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	var result *mongo.UpdateResult
+	err = metrics.TimeMongoOp("products", "update", func() error {
+		var e error
+		result, e = collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{
+			"$set": set,
+			"$inc": bson.M{"version": 1},
+		})
+		return e
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -154,7 +447,7 @@ func (ctrl *Controller) UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Product updated successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Product patched successfully"})
 }
 
 // DeleteProduct menangani penghapusan produk.
@@ -170,7 +463,26 @@ func (ctrl *Controller) DeleteProduct(c *gin.Context) {
 	}
 
 	collection := ctrl.DB.Collection("products")
-	result, err := collection.DeleteOne(ctx, bson.M{"_id": objectID})
+
+	var existing models.Product
+	err = metrics.TimeMongoOp("products", "findOne", func() error {
+		return collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&existing)
+	})
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var result *mongo.DeleteResult
+	err = metrics.TimeMongoOp("products", "delete", func() error {
+		var e error
+		result, e = collection.DeleteOne(ctx, bson.M{"_id": objectID})
+		return e
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -181,5 +493,7 @@ func (ctrl *Controller) DeleteProduct(c *gin.Context) {
 		return
 	}
 
+	destroyProductImages(ctrl, existing.Images)
+
 	c.JSON(http.StatusOK, gin.H{"message": "Product deleted successfully"})
 }
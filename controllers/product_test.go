@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newUpdateProductRequest membuat gin.Context seperti yang diterima
+// UpdateProduct, dengan :id dan body JSON yang diberikan.
+func newUpdateProductRequest(id string, body []byte) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPut, "/api/products/"+id, bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: id}}
+	return c, w
+}
+
+func TestUpdateProductVersionConflict(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("stale version is rejected with 409", func(mt *mtest.T) {
+		ctrl := &Controller{DB: mt.DB, Logger: zap.NewNop()}
+		id := primitive.NewObjectID()
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.products", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: id},
+			{Key: "name", Value: "Sendal Jepit"},
+			{Key: "version", Value: 3},
+		}))
+		// n: 0 berarti filter {_id, version: clientVersion} tidak cocok dokumen
+		// manapun -- versi yang dikirim klien sudah basi.
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+
+		c, w := newUpdateProductRequest(id.Hex(), []byte(`{"name":"Sendal Jepit Baru","version":2}`))
+		ctrl.UpdateProduct(c)
+
+		if w.Code != http.StatusConflict {
+			t.Fatalf("UpdateProduct() status = %d, want %d; body = %s", w.Code, http.StatusConflict, w.Body.String())
+		}
+	})
+
+	mt.Run("current version succeeds", func(mt *mtest.T) {
+		ctrl := &Controller{DB: mt.DB, Logger: zap.NewNop()}
+		id := primitive.NewObjectID()
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.products", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: id},
+			{Key: "name", Value: "Sendal Jepit"},
+			{Key: "version", Value: 3},
+		}))
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		c, w := newUpdateProductRequest(id.Hex(), []byte(`{"name":"Sendal Jepit Baru","version":3}`))
+		ctrl.UpdateProduct(c)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("UpdateProduct() status = %d, want %d; body = %s", w.Code, http.StatusOK, w.Body.String())
+		}
+	})
+}
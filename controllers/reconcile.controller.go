@@ -0,0 +1,18 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetReconcileStatus mengembalikan ringkasan eksekusi terakhir dari job
+// reconciliation asset Cloudinary, untuk dipantau admin.
+func (ctrl *Controller) GetReconcileStatus(c *gin.Context) {
+	if ctrl.Reconciler == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Reconciliation job is not running"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": ctrl.Reconciler.Status()})
+}
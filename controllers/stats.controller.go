@@ -42,7 +42,7 @@ func (ctrl *Controller) GetStats(c *gin.Context) {
 	pipeline := []bson.M{
 		{"$group": bson.M{
 			"_id":   nil,
-			"total": bson.M{"$sum": bson.M{"$multiply": []string{"$price", "$stock"}}},
+			"total": bson.M{"$sum": bson.M{"$multiply": []interface{}{"$price", "$stock"}}},
 		}},
 	}
 	cursor, err := productsCollection.Aggregate(ctx, pipeline)
@@ -0,0 +1,161 @@
+// File: internal/cron/reconcile.go
+package cron
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/cloudinary/cloudinary-go/v2/api"
+	"github.com/cloudinary/cloudinary-go/v2/api/admin"
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ReconcileStatus merangkum hasil eksekusi terakhir dari job reconciliation.
+type ReconcileStatus struct {
+	LastRunAt      time.Time     `json:"last_run_at"`
+	Duration       time.Duration `json:"duration_ms"`
+	OrphansFound   int           `json:"orphans_found"`
+	OrphansDeleted int           `json:"orphans_deleted"`
+	LastError      string        `json:"last_error,omitempty"`
+}
+
+// AssetReconciler membandingkan asset Cloudinary di bawah satu folder dengan
+// public_id yang masih dipakai produk, lalu membersihkan yang sudah yatim.
+// Mengikuti pola AgentCron: status disimpan di sync.Map agar aman diakses
+// lintas goroutine tanpa mengunci seluruh struct, dan isRunning mencegah dua
+// eksekusi tumpang tindih saat satu run berjalan lama.
+type AssetReconciler struct {
+	db     *mongo.Database
+	cld    *cloudinary.Cloudinary
+	folder string
+	state  sync.Map // "isRunning" -> bool, "status" -> ReconcileStatus
+}
+
+// NewAssetReconciler membuat reconciler untuk folder Cloudinary tertentu.
+func NewAssetReconciler(db *mongo.Database, cld *cloudinary.Cloudinary, folder string) *AssetReconciler {
+	return &AssetReconciler{db: db, cld: cld, folder: folder}
+}
+
+// Start menjalankan reconciliation setiap `interval`, mis. time.Hour untuk
+// meniru jadwal "@every 1h". Pemanggil bertanggung jawab menghentikan lewat ctx.
+func (r *AssetReconciler) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.runOnce(ctx)
+			}
+		}
+	}()
+}
+
+// Status mengembalikan hasil run terakhir, atau nilai kosong jika belum pernah berjalan.
+func (r *AssetReconciler) Status() ReconcileStatus {
+	if v, ok := r.state.Load("status"); ok {
+		return v.(ReconcileStatus)
+	}
+	return ReconcileStatus{}
+}
+
+func (r *AssetReconciler) runOnce(ctx context.Context) {
+	if running, _ := r.state.Load("isRunning"); running == true {
+		return
+	}
+	r.state.Store("isRunning", true)
+	defer r.state.Store("isRunning", false)
+
+	start := time.Now()
+	status := ReconcileStatus{LastRunAt: start}
+
+	cloudinaryIDs, err := r.listCloudinaryPublicIDs(ctx)
+	if err != nil {
+		status.LastError = err.Error()
+		status.Duration = time.Since(start)
+		r.state.Store("status", status)
+		log.Println("reconcile: failed to list Cloudinary assets:", err)
+		return
+	}
+
+	usedIDs, err := r.listUsedPublicIDs(ctx)
+	if err != nil {
+		status.LastError = err.Error()
+		status.Duration = time.Since(start)
+		r.state.Store("status", status)
+		log.Println("reconcile: failed to list product images:", err)
+		return
+	}
+
+	deleted := 0
+	for _, publicID := range cloudinaryIDs {
+		if usedIDs[publicID] {
+			continue
+		}
+		status.OrphansFound++
+		if _, err := r.cld.Upload.Destroy(ctx, uploader.DestroyParams{PublicID: publicID}); err != nil {
+			log.Println("reconcile: failed to destroy orphan", publicID, err)
+			continue
+		}
+		deleted++
+	}
+	status.OrphansDeleted = deleted
+	status.Duration = time.Since(start)
+
+	r.state.Store("status", status)
+}
+
+func (r *AssetReconciler) listCloudinaryPublicIDs(ctx context.Context) ([]string, error) {
+	var publicIDs []string
+	nextCursor := ""
+	for {
+		resp, err := r.cld.Admin.Assets(ctx, admin.AssetsParams{
+			AssetType:  api.Image,
+			Prefix:     r.folder,
+			MaxResults: 500,
+			NextCursor: nextCursor,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, asset := range resp.Assets {
+			publicIDs = append(publicIDs, asset.PublicID)
+		}
+		if resp.NextCursor == "" {
+			break
+		}
+		nextCursor = resp.NextCursor
+	}
+	return publicIDs, nil
+}
+
+func (r *AssetReconciler) listUsedPublicIDs(ctx context.Context) (map[string]bool, error) {
+	cursor, err := r.db.Collection("products").Find(ctx, bson.M{}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	used := map[string]bool{}
+	for cursor.Next(ctx) {
+		var doc struct {
+			Images []struct {
+				PublicID string `bson:"public_id"`
+			} `bson:"images"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			continue
+		}
+		for _, img := range doc.Images {
+			used[img.PublicID] = true
+		}
+	}
+	return used, cursor.Err()
+}
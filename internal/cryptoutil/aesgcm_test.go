@@ -0,0 +1,45 @@
+package cryptoutil
+
+import "testing"
+
+func TestEncryptDecryptStringRoundTrip(t *testing.T) {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i)
+	}
+
+	const plaintext = "JBSWY3DPEHPK3PXP"
+
+	encrypted, err := EncryptString(key, plaintext)
+	if err != nil {
+		t.Fatalf("EncryptString() error = %v", err)
+	}
+
+	decrypted, err := DecryptString(key, encrypted)
+	if err != nil {
+		t.Fatalf("DecryptString() error = %v", err)
+	}
+	if decrypted != plaintext {
+		t.Errorf("DecryptString() = %q, want %q", decrypted, plaintext)
+	}
+}
+
+func TestDecryptStringWrongKeyFails(t *testing.T) {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	wrongKey := make([]byte, 32)
+	for i := range wrongKey {
+		wrongKey[i] = byte(i + 1)
+	}
+
+	encrypted, err := EncryptString(key, "secret")
+	if err != nil {
+		t.Fatalf("EncryptString() error = %v", err)
+	}
+
+	if _, err := DecryptString(wrongKey, encrypted); err == nil {
+		t.Error("DecryptString() with wrong key succeeded, want error")
+	}
+}
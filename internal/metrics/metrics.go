@@ -0,0 +1,82 @@
+// File: internal/metrics/metrics.go
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// HTTPRequestsTotal menghitung request per route/method/status, dipakai baik
+// oleh middleware Gin maupun wrapper mux stdlib agar kedua dunia muncul di
+// satu dashboard Prometheus yang sama.
+var HTTPRequestsTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total jumlah HTTP request yang diterima, dilabeli route/method/status.",
+	},
+	[]string{"route", "method", "status"},
+)
+
+// HTTPRequestDuration mengukur latency request dalam detik.
+var HTTPRequestDuration = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Distribusi latency HTTP request dalam detik.",
+		Buckets: prometheus.DefBuckets,
+	},
+	[]string{"route", "method"},
+)
+
+// MongoOperationDuration mengukur latency operasi MongoDB, dilabeli koleksi
+// dan jenis operasi (mis. collection="products", op="find").
+var MongoOperationDuration = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "mongo_operation_duration_seconds",
+		Help:    "Distribusi latency operasi MongoDB dalam detik, dilabeli collection/op.",
+		Buckets: prometheus.DefBuckets,
+	},
+	[]string{"collection", "op"},
+)
+
+// UploadsTotal melacak jumlah upload gambar yang sedang/telah diproses.
+var UploadsTotal = promauto.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "uploads_total",
+		Help: "Jumlah upload gambar yang diterima sejak server start.",
+	},
+)
+
+// ActiveConnections melacak jumlah request HTTP yang sedang diproses saat ini.
+var ActiveConnections = promauto.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "active_connections",
+		Help: "Jumlah request HTTP yang sedang diproses saat ini.",
+	},
+)
+
+// Observe mencatat hasil satu request ke HTTPRequestsTotal/HTTPRequestDuration.
+// Dipakai oleh middleware Gin maupun wrapper mux stdlib sehingga logika
+// pencatatan metrik tidak perlu diduplikasi di kedua stack.
+func Observe(route, method string, status int, elapsed time.Duration) {
+	HTTPRequestsTotal.WithLabelValues(route, method, strconv.Itoa(status)).Inc()
+	HTTPRequestDuration.WithLabelValues(route, method).Observe(elapsed.Seconds())
+}
+
+// TimeMongoOp menjalankan fn sambil mencatat durasinya ke MongoOperationDuration,
+// dilabeli nama koleksi dan operasi (mis. "products", "find").
+func TimeMongoOp(collection, op string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	MongoOperationDuration.WithLabelValues(collection, op).Observe(time.Since(start).Seconds())
+	return err
+}
+
+// Handler mengembalikan http.Handler standar Prometheus untuk dipasang di /metrics.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,64 @@
+// File: internal/password/breach.go
+package password
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// hibpRangeURL adalah endpoint k-anonymity Have I Been Pwned: hanya 5 karakter
+// pertama hash SHA-1 password yang dikirim, sehingga HIBP tidak pernah melihat
+// password atau hash lengkapnya.
+const hibpRangeURL = "https://api.pwnedpasswords.com/range/%s"
+
+// BreachChecker memeriksa apakah sebuah password pernah muncul di kebocoran
+// data yang diindeks HIBP. HTTPClient bisa diganti (mis. dengan stub di test)
+// supaya pemanggilan jaringan nyata tidak dibutuhkan untuk memverifikasi logika.
+type BreachChecker struct {
+	HTTPClient *http.Client
+}
+
+// NewBreachChecker membuat BreachChecker dengan http.Client bawaan jika client
+// tidak diberikan.
+func NewBreachChecker(client *http.Client) *BreachChecker {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &BreachChecker{HTTPClient: client}
+}
+
+// IsPwned mengembalikan true jika password ditemukan di database HIBP.
+func (b *BreachChecker) IsPwned(ctx context.Context, candidate string) (bool, error) {
+	sum := sha1.Sum([]byte(candidate))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(hibpRangeURL, prefix), nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := b.HTTPClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("hibp range lookup failed: status %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if candidateSuffix, _, found := strings.Cut(line, ":"); found && candidateSuffix == suffix {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
@@ -0,0 +1,84 @@
+// File: internal/password/policy.go
+package password
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Policy mendefinisikan aturan kekuatan password. Diekspos sebagai struct
+// (bukan fungsi tetap) supaya bisa dikustomisasi per deployment lewat
+// AppConfig nantinya, dan supaya test bisa memakai policy yang lebih longgar
+// tanpa mengubah DefaultPolicy.
+type Policy struct {
+	MinLength        int
+	RequireUpper     bool
+	RequireLower     bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	DisallowIdentity bool // tolak jika password mengandung username/email
+}
+
+// DefaultPolicy adalah aturan bawaan yang dipakai Register/ChangePassword.
+func DefaultPolicy() Policy {
+	return Policy{
+		MinLength:        8,
+		RequireUpper:     true,
+		RequireLower:     true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+		DisallowIdentity: true,
+	}
+}
+
+// Validate mengembalikan daftar pesan error untuk setiap aturan yang
+// dilanggar password terhadap username/email pemiliknya (keduanya boleh
+// kosong, mis. saat policy dipakai di luar konteks registrasi). Daftar
+// kosong berarti password valid.
+func (p Policy) Validate(candidate, username, email string) []string {
+	var errs []string
+
+	if len(candidate) < p.MinLength {
+		errs = append(errs, "password must be at least "+strconv.Itoa(p.MinLength)+" characters long")
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range candidate {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	if p.RequireUpper && !hasUpper {
+		errs = append(errs, "password must contain at least one uppercase letter")
+	}
+	if p.RequireLower && !hasLower {
+		errs = append(errs, "password must contain at least one lowercase letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		errs = append(errs, "password must contain at least one digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		errs = append(errs, "password must contain at least one symbol")
+	}
+
+	if p.DisallowIdentity {
+		lower := strings.ToLower(candidate)
+		if username != "" && strings.Contains(lower, strings.ToLower(username)) {
+			errs = append(errs, "password must not contain the username")
+		}
+		if email != "" && strings.Contains(lower, strings.ToLower(email)) {
+			errs = append(errs, "password must not contain the email address")
+		}
+	}
+
+	return errs
+}
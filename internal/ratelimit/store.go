@@ -0,0 +1,203 @@
+// File: internal/ratelimit/store.go
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Store abstrak tempat state rate limiting per-key (bucket, kegagalan
+// beruntun, kuota byte) disimpan. MemoryStore di bawah cukup untuk satu
+// instance server; implementasi lain (mis. Redis-backed, untuk deployment
+// multi-instance) cukup memenuhi interface ini tanpa mengubah middleware
+// pemanggilnya.
+type Store interface {
+	// Allow mengecek dan mengonsumsi satu token dari bucket milik key,
+	// membuat bucket baru dengan rps/burst tersebut jika key belum punya bucket.
+	Allow(key string, rps rate.Limit, burst int) bool
+
+	// RecordFailure mencatat satu kegagalan (mis. password salah) untuk key
+	// dan mengembalikan jumlah kegagalan beruntun sejauh ini.
+	RecordFailure(key string) int
+
+	// ResetFailures menghapus catatan kegagalan key, dipanggil setelah
+	// percobaan berhasil.
+	ResetFailures(key string)
+
+	// LockedUntil mengembalikan waktu key boleh dicoba lagi dan true jika
+	// key sedang terkena lockout akibat kegagalan beruntun.
+	LockedUntil(key string) (time.Time, bool)
+
+	// AddBytes menambah kuota byte kumulatif key pada window berjalan dan
+	// mengembalikan total byte yang sudah terpakai di window tersebut.
+	// Window baru dimulai otomatis begitu window sebelumnya kedaluwarsa.
+	AddBytes(key string, n int64, window time.Duration) int64
+}
+
+// lockoutThreshold adalah jumlah kegagalan beruntun sebelum key mulai
+// terkena lockout eksponensial, bukan sekadar ditolak rate limiter.
+const lockoutThreshold = 10
+
+// baseLockout adalah durasi lockout untuk kegagalan pertama setelah
+// melewati lockoutThreshold; durasi berikutnya berlipat dua tiap kegagalan
+// tambahan, dibatasi maxLockout agar key tidak terkunci permanen.
+const baseLockout = 30 * time.Second
+const maxLockout = time.Hour
+
+type bucketEntry struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+type failureEntry struct {
+	count       int
+	lockedUntil time.Time
+	lastFailure time.Time
+}
+
+type byteEntry struct {
+	total       int64
+	windowStart time.Time
+	lastUsed    time.Time
+}
+
+// MemoryStore adalah implementasi Store in-memory, dengan entry yang
+// dibuang otomatis setelah idle lebih lama dari ttl supaya map tidak
+// tumbuh tanpa batas saat dipakai untuk key per-IP/per-akun.
+type MemoryStore struct {
+	mu               sync.Mutex
+	ttl              time.Duration
+	lockoutThreshold int
+	baseLockout      time.Duration
+	maxLockout       time.Duration
+	buckets          map[string]*bucketEntry
+	failures         map[string]*failureEntry
+	byteQuota        map[string]*byteEntry
+}
+
+// NewMemoryStore membuat MemoryStore dengan ambang lockout bawaan
+// (lockoutThreshold/baseLockout/maxLockout) dan menjalankan goroutine
+// pembersih yang berjalan tiap ttl/2 untuk membuang entry yang sudah lama idle.
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return NewMemoryStoreWithLockout(ttl, lockoutThreshold, baseLockout, maxLockout)
+}
+
+// NewMemoryStoreWithLockout sama seperti NewMemoryStore, tapi ambang dan
+// durasi lockout-nya bisa dikustomisasi -- dipakai untuk lockout akun login
+// yang nilainya berasal dari AppConfig (LoginMaxAttempts/LoginLockout), alih-alih
+// konstanta bawaan paket ini.
+func NewMemoryStoreWithLockout(ttl time.Duration, threshold int, base, max time.Duration) *MemoryStore {
+	s := &MemoryStore{
+		ttl:              ttl,
+		lockoutThreshold: threshold,
+		baseLockout:      base,
+		maxLockout:       max,
+		buckets:          make(map[string]*bucketEntry),
+		failures:         make(map[string]*failureEntry),
+		byteQuota:        make(map[string]*byteEntry),
+	}
+	go s.evictLoop()
+	return s
+}
+
+func (s *MemoryStore) evictLoop() {
+	interval := s.ttl / 2
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		cutoff := time.Now().Add(-s.ttl)
+		s.mu.Lock()
+		for k, e := range s.buckets {
+			if e.lastUsed.Before(cutoff) {
+				delete(s.buckets, k)
+			}
+		}
+		for k, e := range s.failures {
+			if e.lastFailure.Before(cutoff) && time.Now().After(e.lockedUntil) {
+				delete(s.failures, k)
+			}
+		}
+		for k, e := range s.byteQuota {
+			if e.lastUsed.Before(cutoff) {
+				delete(s.byteQuota, k)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (s *MemoryStore) Allow(key string, rps rate.Limit, burst int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.buckets[key]
+	if !ok {
+		e = &bucketEntry{limiter: rate.NewLimiter(rps, burst)}
+		s.buckets[key] = e
+	}
+	e.lastUsed = time.Now()
+	return e.limiter.Allow()
+}
+
+func (s *MemoryStore) RecordFailure(key string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.failures[key]
+	if !ok {
+		e = &failureEntry{}
+		s.failures[key] = e
+	}
+	e.count++
+	e.lastFailure = time.Now()
+
+	if e.count >= s.lockoutThreshold {
+		shift := e.count - s.lockoutThreshold
+		lockout := s.baseLockout << shift
+		if lockout <= 0 || lockout > s.maxLockout {
+			lockout = s.maxLockout
+		}
+		e.lockedUntil = time.Now().Add(lockout)
+	}
+	return e.count
+}
+
+func (s *MemoryStore) ResetFailures(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.failures, key)
+}
+
+func (s *MemoryStore) LockedUntil(key string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.failures[key]
+	if !ok || e.lockedUntil.IsZero() {
+		return time.Time{}, false
+	}
+	if time.Now().After(e.lockedUntil) {
+		return time.Time{}, false
+	}
+	return e.lockedUntil, true
+}
+
+func (s *MemoryStore) AddBytes(key string, n int64, window time.Duration) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	e, ok := s.byteQuota[key]
+	if !ok || now.Sub(e.windowStart) > window {
+		e = &byteEntry{windowStart: now}
+		s.byteQuota[key] = e
+	}
+	e.total += n
+	e.lastUsed = now
+	return e.total
+}
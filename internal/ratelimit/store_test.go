@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordFailureLocksOutAtThreshold(t *testing.T) {
+	s := NewMemoryStoreWithLockout(time.Hour, 3, time.Second, time.Minute)
+
+	if _, locked := s.LockedUntil("k"); locked {
+		t.Fatalf("LockedUntil() = locked before any failure, want unlocked")
+	}
+
+	for i := 1; i < 3; i++ {
+		count := s.RecordFailure("k")
+		if count != i {
+			t.Fatalf("RecordFailure() = %d, want %d", count, i)
+		}
+		if _, locked := s.LockedUntil("k"); locked {
+			t.Fatalf("LockedUntil() = locked after %d failure(s), want unlocked (threshold is 3)", i)
+		}
+	}
+
+	count := s.RecordFailure("k")
+	if count != 3 {
+		t.Fatalf("RecordFailure() = %d, want 3", count)
+	}
+	until, locked := s.LockedUntil("k")
+	if !locked {
+		t.Fatalf("LockedUntil() = unlocked after the 3rd failure, want locked (>= threshold)")
+	}
+	if until.Before(time.Now()) {
+		t.Errorf("LockedUntil() = %v, want a time in the future", until)
+	}
+}
+
+func TestRecordFailureBackoffDoublesAndCaps(t *testing.T) {
+	s := NewMemoryStoreWithLockout(time.Hour, 1, time.Second, 4*time.Second)
+
+	s.RecordFailure("k") // 1st failure: shift 0, lockout = 1s
+	until1, _ := s.LockedUntil("k")
+
+	s.RecordFailure("k") // 2nd failure: shift 1, lockout = 2s
+	until2, _ := s.LockedUntil("k")
+
+	s.RecordFailure("k") // 3rd failure: shift 2, lockout = 4s (== maxLockout)
+	until3, _ := s.LockedUntil("k")
+
+	s.RecordFailure("k") // 4th failure: shift 3, lockout would be 8s, capped to 4s
+	until4, _ := s.LockedUntil("k")
+
+	if !until2.After(until1) {
+		t.Errorf("2nd lockout (%v) should extend past the 1st (%v)", until2, until1)
+	}
+	if !until3.After(until2) {
+		t.Errorf("3rd lockout (%v) should extend past the 2nd (%v)", until3, until2)
+	}
+
+	maxLockoutFromNow := time.Now().Add(4 * time.Second)
+	if until4.After(maxLockoutFromNow.Add(time.Second)) {
+		t.Errorf("4th lockout (%v) should be capped at maxLockout, want around %v", until4, maxLockoutFromNow)
+	}
+}
+
+func TestResetFailuresClearsLockout(t *testing.T) {
+	s := NewMemoryStoreWithLockout(time.Hour, 2, time.Minute, time.Hour)
+
+	s.RecordFailure("k")
+	s.RecordFailure("k")
+	if _, locked := s.LockedUntil("k"); !locked {
+		t.Fatalf("LockedUntil() = unlocked after reaching threshold, want locked")
+	}
+
+	s.ResetFailures("k")
+	if _, locked := s.LockedUntil("k"); locked {
+		t.Errorf("LockedUntil() = locked after ResetFailures, want unlocked")
+	}
+	if count := s.RecordFailure("k"); count != 1 {
+		t.Errorf("RecordFailure() after reset = %d, want 1 (counter should restart)", count)
+	}
+}
+
+func TestLockedUntilExpires(t *testing.T) {
+	s := NewMemoryStoreWithLockout(time.Hour, 1, 10*time.Millisecond, time.Minute)
+
+	s.RecordFailure("k")
+	if _, locked := s.LockedUntil("k"); !locked {
+		t.Fatalf("LockedUntil() = unlocked right after lockout, want locked")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, locked := s.LockedUntil("k"); locked {
+		t.Errorf("LockedUntil() = locked after lockout window passed, want unlocked")
+	}
+}
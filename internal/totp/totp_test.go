@@ -0,0 +1,37 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerifyWindowTolerance(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+
+	now := time.Unix(1_700_000_000, 0)
+
+	within := now.Add(-windowTolerance * step)
+	outside := now.Add(-(windowTolerance + 1) * step)
+
+	withinCode, err := code(secret, within)
+	if err != nil {
+		t.Fatalf("code(within) error = %v", err)
+	}
+	outsideCode, err := code(secret, outside)
+	if err != nil {
+		t.Fatalf("code(outside) error = %v", err)
+	}
+
+	if !Verify(secret, withinCode, now) {
+		t.Errorf("Verify() = false for a code %d step(s) away, want true", windowTolerance)
+	}
+	if Verify(secret, outsideCode, now) {
+		t.Errorf("Verify() = true for a code %d step(s) away, want false", windowTolerance+1)
+	}
+	if Verify(secret, "000000", now) && withinCode != "000000" && outsideCode != "000000" {
+		t.Errorf("Verify() = true for an unrelated code, want false")
+	}
+}
@@ -0,0 +1,223 @@
+// File: middleware/auth.go
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"pitipaw-backend/models"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/o1egl/paseto"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AdminContextKey adalah key yang dipakai menyimpan admin yang sedang login di gin.Context.
+const AdminContextKey = "admin"
+
+// AccessClaimsContextKey adalah key yang dipakai menyimpan klaim ringkas
+// access token (jti + masa berlaku) yang sedang dipakai request ini.
+const AccessClaimsContextKey = "access_claims"
+
+// AccessClaims menampung klaim ringkas access token yang sedang aktif,
+// dipakai Logout untuk mencabut token tersebut lewat revoked_access_tokens
+// tanpa perlu mendekode ulang token PASETO-nya.
+type AccessClaims struct {
+	Jti        string
+	Expiration time.Time
+}
+
+// TokenFooterContextKey adalah key yang dipakai menyimpan TokenFooter yang
+// diverifikasi AuthMiddleware untuk request ini.
+const TokenFooterContextKey = "token_footer"
+
+// TokenFooter dikodekan sebagai JSON di footer PASETO (bukan string statis
+// seperti sebelumnya), membawa role dan daftar permission admin pada saat
+// token diterbitkan, supaya RequirePermission bisa dicek tanpa query DB
+// tambahan per request.
+type TokenFooter struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+// HasPermission mengembalikan true jika token berhak atas permission
+// tertentu: superadmin selalu berhak (wildcard), selain itu dicek lewat
+// daftar permission eksplisit (termasuk wildcard "*" di dalamnya).
+func (f *TokenFooter) HasPermission(permission string) bool {
+	if f.Role == "superadmin" {
+		return true
+	}
+	for _, p := range f.Permissions {
+		if p == permission || p == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// extractPasetoToken mengambil token dari header Authorization: Bearer ... atau cookie auth.
+func extractPasetoToken(c *gin.Context) string {
+	header := c.GetHeader("Authorization")
+	if strings.HasPrefix(header, "Bearer ") {
+		return strings.TrimPrefix(header, "Bearer ")
+	}
+	if cookie, err := c.Cookie("auth"); err == nil {
+		return cookie
+	}
+	return ""
+}
+
+// Authenticate memverifikasi token PASETO pada request, memuat admin terkait,
+// dan menyimpan admin/klaim/TokenFooter di context. Menulis response error
+// sendiri dan mengembalikan false jika verifikasi gagal -- pemanggil harus
+// langsung return begitu false diterima. Dipisah dari AuthMiddleware supaya
+// handler yang rutenya tidak selalu lewat middleware (mis. Register, yang
+// hanya perlu token untuk pendaftaran admin kedua dan seterusnya) bisa
+// memverifikasi token yang sama tanpa duplikasi logika.
+func Authenticate(c *gin.Context, db *mongo.Database, secretKey []byte) bool {
+	tokenString := extractPasetoToken(c)
+	if tokenString == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		c.Abort()
+		return false
+	}
+
+	var jsonToken paseto.JSONToken
+	var footerRaw string
+	if err := paseto.NewV2().Decrypt(tokenString, secretKey, &jsonToken, &footerRaw); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		c.Abort()
+		return false
+	}
+
+	if time.Now().After(jsonToken.Expiration) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token expired"})
+		c.Abort()
+		return false
+	}
+
+	if jsonToken.Jti != "" {
+		count, err := db.Collection("revoked_access_tokens").CountDocuments(c.Request.Context(), bson.M{"jti": jsonToken.Jti})
+		if err == nil && count > 0 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+			c.Abort()
+			return false
+		}
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(jsonToken.Subject)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token subject"})
+		c.Abort()
+		return false
+	}
+
+	var admin models.Admin
+	if err := db.Collection("admins").FindOne(c.Request.Context(), bson.M{"_id": objectID}).Decode(&admin); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin not found"})
+		c.Abort()
+		return false
+	}
+	admin.Password = ""
+
+	var footer TokenFooter
+	_ = json.Unmarshal([]byte(footerRaw), &footer)
+
+	c.Set(AdminContextKey, &admin)
+	c.Set(AccessClaimsContextKey, &AccessClaims{Jti: jsonToken.Jti, Expiration: jsonToken.Expiration})
+	c.Set(TokenFooterContextKey, &footer)
+	return true
+}
+
+// AuthMiddleware memverifikasi token PASETO yang diterbitkan Controller.Login
+// lewat Authenticate, memuat admin terkait dari database, dan menyimpannya
+// di context untuk dipakai handler (mis. audit logging lewat GetAdminFromCtx).
+func AuthMiddleware(db *mongo.Database, secretKey []byte) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !Authenticate(c, db, secretKey) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequirePermission menolak request yang token-nya tidak punya permission
+// tersebut (superadmin selalu lolos lewat wildcard). Harus dipasang setelah
+// AuthMiddleware karena bergantung pada TokenFooter di context.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		footer, ok := GetTokenFooterFromCtx(c)
+		if !ok || !footer.HasPermission(permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSelfOrPermission mengizinkan request hanya jika parameter URL :id
+// sama dengan ID admin pelaku (mengedit akun sendiri), atau token-nya punya
+// permission tertentu (mengedit akun admin lain). Tanpa ini, passwordConfirm
+// saja tidak cukup -- itu hanya memverifikasi password pelaku, bukan relasi
+// pelaku terhadap akun target, sehingga admin berprivilege rendah bisa
+// menimpa username/password admin lain selama tahu ID-nya. Harus dipasang
+// setelah AuthMiddleware.
+func RequireSelfOrPermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actingAdmin, ok := GetAdminFromCtx(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			c.Abort()
+			return
+		}
+		if actingAdmin.ID.Hex() == c.Param("id") {
+			c.Next()
+			return
+		}
+
+		footer, ok := GetTokenFooterFromCtx(c)
+		if !ok || !footer.HasPermission(permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// GetAdminFromCtx mengembalikan admin yang diautentikasi AuthMiddleware, untuk audit logging.
+func GetAdminFromCtx(c *gin.Context) (*models.Admin, bool) {
+	admin, ok := c.Get(AdminContextKey)
+	if !ok {
+		return nil, false
+	}
+	a, ok := admin.(*models.Admin)
+	return a, ok
+}
+
+// GetTokenFooterFromCtx mengembalikan TokenFooter (role + permissions) dari
+// access token yang diverifikasi Authenticate/AuthMiddleware untuk request ini.
+func GetTokenFooterFromCtx(c *gin.Context) (*TokenFooter, bool) {
+	footer, ok := c.Get(TokenFooterContextKey)
+	if !ok {
+		return nil, false
+	}
+	f, ok := footer.(*TokenFooter)
+	return f, ok
+}
+
+// GetAccessClaimsFromCtx mengembalikan klaim access token yang diverifikasi
+// AuthMiddleware untuk request ini, dipakai Logout untuk mencabut token yang
+// sedang aktif.
+func GetAccessClaimsFromCtx(c *gin.Context) (*AccessClaims, bool) {
+	claims, ok := c.Get(AccessClaimsContextKey)
+	if !ok {
+		return nil, false
+	}
+	a, ok := claims.(*AccessClaims)
+	return a, ok
+}
@@ -0,0 +1,153 @@
+// File: middleware/idempotency.go
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const idempotencyKeyTTL = 24 * time.Hour
+
+// idempotencyRecord menyimpan hasil eksekusi pertama sebuah Idempotency-Key
+// agar retry dengan key yang sama bisa direplay tanpa menjalankan ulang handler.
+// Status dimulai sebagai "pending" saat key direservasi (sebelum handler
+// dijalankan) dan diubah jadi "completed" setelah response tersimpan, supaya
+// dua request paralel dengan key yang sama tidak bisa lolos bersamaan --
+// yang kedua kalah di unique index pada InsertOne reservasi, bukan di
+// pengecekan FindOne yang rentan race.
+type idempotencyRecord struct {
+	Key            string    `bson:"key"`
+	UserID         string    `bson:"user_id,omitempty"`
+	RequestHash    string    `bson:"request_hash"`
+	Status         string    `bson:"status"`
+	ResponseStatus int       `bson:"response_status,omitempty"`
+	ResponseBody   []byte    `bson:"response_body,omitempty"`
+	CreatedAt      time.Time `bson:"created_at"`
+}
+
+const (
+	idempotencyStatusPending   = "pending"
+	idempotencyStatusCompleted = "completed"
+)
+
+// bodyCapturingWriter membungkus gin.ResponseWriter agar status dan body yang
+// ditulis controller bisa disimpan tanpa controller perlu diubah.
+type bodyCapturingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyCapturingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// EnsureIdempotencyIndexes membuat index TTL pada koleksi idempotency_keys
+// agar record retry kadaluarsa otomatis setelah 24 jam.
+func EnsureIdempotencyIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("idempotency_keys").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(idempotencyKeyTTL.Seconds())),
+		},
+	})
+	return err
+}
+
+// Idempotency mencegah request yang di-retry (mis. POST /api/products dari
+// jaringan mobile yang putus-nyambung) menghasilkan insert atau upload ganda.
+// Request dengan Idempotency-Key yang sama dan body yang sama akan direplay
+// dari response tersimpan; body yang berbeda dianggap konflik.
+func Idempotency(db *mongo.Database) gin.HandlerFunc {
+	collection := db.Collection("idempotency_keys")
+
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		rawBody, err := c.GetRawData()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewBuffer(rawBody))
+
+		hash := sha256.Sum256(rawBody)
+		requestHash := hex.EncodeToString(hash[:])
+
+		ctx := c.Request.Context()
+
+		// Reservasi key lebih dulu lewat InsertOne yang dijaga unique index pada
+		// "key" -- ini titik atomik satu-satunya yang menentukan siapa yang
+		// berhak menjalankan handler. Request kedua yang datang bersamaan akan
+		// kalah di sini, sebelum sempat memanggil handler atau upload apa pun.
+		reservation := idempotencyRecord{
+			Key:         key,
+			UserID:      c.GetString("admin_id"),
+			RequestHash: requestHash,
+			Status:      idempotencyStatusPending,
+			CreatedAt:   time.Now(),
+		}
+		if _, err := collection.InsertOne(ctx, reservation); err != nil {
+			if !mongo.IsDuplicateKeyError(err) {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				c.Abort()
+				return
+			}
+
+			var existing idempotencyRecord
+			if err := collection.FindOne(ctx, bson.M{"key": key}).Decode(&existing); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				c.Abort()
+				return
+			}
+			if existing.RequestHash != requestHash {
+				c.JSON(http.StatusConflict, gin.H{"error": "Idempotency-Key already used with a different request"})
+				c.Abort()
+				return
+			}
+			if existing.Status != idempotencyStatusCompleted {
+				c.JSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is already in progress"})
+				c.Abort()
+				return
+			}
+			c.Data(existing.ResponseStatus, "application/json", existing.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		writer := &bodyCapturingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		update := bson.M{"$set": bson.M{
+			"status":          idempotencyStatusCompleted,
+			"response_status": writer.Status(),
+			"response_body":   writer.body.Bytes(),
+		}}
+		if _, err := collection.UpdateOne(context.Background(), bson.M{"key": key}, update); err != nil {
+			// Response sudah terkirim ke klien; kegagalan di sini hanya berarti
+			// retry berikutnya akan menjalankan handler ulang alih-alih replay.
+			_ = err
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// File: middleware/logging.go
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RequestIDKey adalah key yang dipakai untuk menyimpan request ID di gin.Context.
+const RequestIDKey = "request_id"
+
+// RequestIDHeader adalah nama header request/response yang membawa request ID.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID menghasilkan (atau meneruskan) satu request ID per request,
+// menyimpannya di context, dan menggemakannya di response header agar bisa
+// dilacak lintas sistem (log aggregator, tracing, dsb).
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetHeader(RequestIDHeader)
+		if rid == "" {
+			rid = uuid.NewString()
+		}
+		c.Set(RequestIDKey, rid)
+		c.Writer.Header().Set(RequestIDHeader, rid)
+		c.Next()
+	}
+}
+
+// StructuredLogging menggantikan gin.Logger() bawaan dengan satu baris log
+// terstruktur per request lewat Zap, lengkap dengan request ID untuk korelasi.
+func StructuredLogging(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		logger.Info("request",
+			zap.String("request_id", c.GetString(RequestIDKey)),
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", c.Writer.Status()),
+			zap.Duration("latency", time.Since(start)),
+			zap.Int("bytes", c.Writer.Size()),
+			zap.String("remote_ip", c.ClientIP()),
+		)
+	}
+}
@@ -0,0 +1,30 @@
+// File: middleware/metrics.go
+package middleware
+
+import (
+	"time"
+
+	"pitipaw-backend/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics mencatat setiap request ke Prometheus lewat internal/metrics, memakai
+// c.FullPath() (pola rute, bukan URL mentah) supaya path dengan parameter
+// seperti /api/products/:id tidak memecah metrik jadi satu seri per ID.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		metrics.ActiveConnections.Inc()
+		defer metrics.ActiveConnections.Dec()
+
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.Observe(route, c.Request.Method, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,77 @@
+// File: middleware/password_confirmation.go
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"pitipaw-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// peekCurrentPassword membaca current_password dari header X-Confirm-Password
+// atau body JSON tanpa mengonsumsi body request, supaya handler masih bisa
+// membaca body yang sama lewat ShouldBindJSON.
+func peekCurrentPassword(c *gin.Context) string {
+	if header := c.GetHeader("X-Confirm-Password"); header != "" {
+		return header
+	}
+
+	raw, err := c.GetRawData()
+	if err != nil {
+		return ""
+	}
+	c.Request.Body = io.NopCloser(bytes.NewBuffer(raw))
+
+	var payload struct {
+		CurrentPassword string `json:"current_password"`
+	}
+	_ = json.Unmarshal(raw, &payload)
+	return payload.CurrentPassword
+}
+
+// RequirePasswordConfirmation mewajibkan admin yang sedang login memasukkan
+// ulang passwordnya (header X-Confirm-Password atau field JSON
+// current_password) sebelum operasi sensitif (hapus/ubah akun admin,
+// enroll/confirm 2FA) dijalankan, supaya token yang bocor tidak bisa
+// langsung mengubah keamanan akun admin tanpa tahu passwordnya. Admin yang
+// diverifikasi adalah pelaku (subjek token, dari AuthMiddleware), bukan akun
+// target di parameter URL. Harus dipasang setelah AuthMiddleware.
+func RequirePasswordConfirmation(db *mongo.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actingAdmin, ok := GetAdminFromCtx(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			c.Abort()
+			return
+		}
+
+		password := peekCurrentPassword(c)
+		if password == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "current_password is required"})
+			c.Abort()
+			return
+		}
+
+		var admin models.Admin
+		if err := db.Collection("admins").FindOne(c.Request.Context(), bson.M{"_id": actingAdmin.ID}).Decode(&admin); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin not found"})
+			c.Abort()
+			return
+		}
+
+		if bcrypt.CompareHashAndPassword([]byte(admin.Password), []byte(password)) != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Incorrect password"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,118 @@
+// File: middleware/ratelimit.go
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"pitipaw-backend/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	xrate "golang.org/x/time/rate"
+)
+
+// uploadQuotaWindow adalah jendela kuota byte kumulatif untuk upload gambar
+// per admin.
+const uploadQuotaWindow = time.Hour
+
+// uploadQuotaBytes adalah batas kumulatif upload gambar per admin per
+// uploadQuotaWindow (200 MB/jam).
+const uploadQuotaBytes = 200 * 1024 * 1024
+
+// credentialKey menggabungkan IP klien dan username yang dicoba, supaya
+// brute force dari satu IP terhadap banyak username (atau sebaliknya, dari
+// banyak IP terhadap satu username) sama-sama kena limit.
+func credentialKey(c *gin.Context, username string) string {
+	return c.ClientIP() + "|" + username
+}
+
+// peekUsername membaca field "username" dari body JSON tanpa mengonsumsi
+// body request, supaya handler (Login/Register) masih bisa membaca body
+// seperti biasa lewat ShouldBindJSON.
+func peekUsername(c *gin.Context) string {
+	raw, err := c.GetRawData()
+	if err != nil {
+		return ""
+	}
+	c.Request.Body = io.NopCloser(bytes.NewBuffer(raw))
+
+	var payload struct {
+		Username string `json:"username"`
+	}
+	_ = json.Unmarshal(raw, &payload)
+	return payload.Username
+}
+
+// writeRateLimited menulis response 429 dengan header Retry-After.
+func writeRateLimited(c *gin.Context, retryAfter time.Duration) {
+	if retryAfter < time.Second {
+		retryAfter = time.Second
+	}
+	c.Header("Retry-After", fmt.Sprintf("%d", int(retryAfter.Seconds())))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests, please try again later"})
+	c.Abort()
+}
+
+// CredentialRateLimit membatasi percobaan login/register per kombinasi IP +
+// username (5/menit), dan menjatuhkan lockout eksponensial pada key yang
+// gagal berkali-kali (brute force credential stuffing). Kegagalan dideteksi
+// dari status response handler setelah c.Next(), sehingga percobaan yang
+// berhasil (2xx) tidak ikut dihitung sebagai kegagalan.
+func CredentialRateLimit(store ratelimit.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		username := peekUsername(c)
+		key := credentialKey(c, username)
+
+		if lockedUntil, locked := store.LockedUntil(key); locked {
+			writeRateLimited(c, time.Until(lockedUntil))
+			return
+		}
+
+		if !store.Allow(key, xrate.Every(12*time.Second), 5) {
+			writeRateLimited(c, 12*time.Second)
+			return
+		}
+
+		c.Next()
+
+		if c.Writer.Status() >= 400 {
+			store.RecordFailure(key)
+		} else {
+			store.ResetFailures(key)
+		}
+	}
+}
+
+// UploadRateLimit membatasi frekuensi (20/menit) dan volume kumulatif
+// (200MB/jam) upload gambar per admin yang terautentikasi. Harus dipasang
+// setelah AuthMiddleware karena bergantung pada admin di context.
+func UploadRateLimit(store ratelimit.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		admin, ok := GetAdminFromCtx(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			c.Abort()
+			return
+		}
+		key := admin.ID.Hex()
+
+		if !store.Allow(key, xrate.Every(3*time.Second), 20) {
+			writeRateLimited(c, 3*time.Second)
+			return
+		}
+
+		if c.Request.ContentLength > 0 {
+			used := store.AddBytes(key, c.Request.ContentLength, uploadQuotaWindow)
+			if used > uploadQuotaBytes {
+				writeRateLimited(c, uploadQuotaWindow)
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
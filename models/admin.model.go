@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Admin mendefinisikan struktur untuk pengguna admin. Role merujuk ke nama
+// dokumen di koleksi roles ("superadmin", "admin", atau role custom lain)
+// yang menentukan daftar permission admin ini.
+//
+// Field MFA* menopang 2FA TOTP: MFASecret adalah secret base32 yang
+// dienkripsi AES-GCM dengan AppConfig.PasetoSecretKey (lihat internal/cryptoutil)
+// supaya dump database saja tidak cukup untuk membaca second factor admin;
+// MFARecoveryCodes adalah kode pemulihan sekali-pakai yang disimpan dalam
+// bentuk hash bcrypt, serupa Admin.Password.
+type Admin struct {
+	ID               primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Username         string             `json:"username" bson:"username"`
+	Email            string             `json:"email" bson:"email"`
+	Password         string             `json:"password" bson:"password"`
+	Role             string             `json:"role" bson:"role"`
+	MFASecret        string             `json:"-" bson:"mfa_secret,omitempty"`
+	MFAEnabled       bool               `json:"mfa_enabled" bson:"mfa_enabled"`
+	MFARecoveryCodes []string           `json:"-" bson:"mfa_recovery_codes,omitempty"`
+	CreatedAt        time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// LoginRequest mendefinisikan struktur untuk permintaan login.
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// RegisterRequest mendefinisikan struktur untuk permintaan registrasi.
+type RegisterRequest struct {
+	Username string `json:"username" binding:"required"`
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// UpdateAdminRequest mendefinisikan struktur untuk permintaan PATCH /api/admins/:id.
+type UpdateAdminRequest struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// ChangePasswordRequest mendefinisikan struktur untuk permintaan ganti password
+// lewat PATCH /api/admins/:id/password.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// LoginVerifyRequest mendefinisikan struktur untuk permintaan langkah kedua
+// login lewat POST /api/login/verify, dipakai saat Login mengembalikan
+// mfa_required.
+type LoginVerifyRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// MFAConfirmRequest dipakai POST /api/admins/me/mfa/confirm untuk
+// membuktikan admin berhasil memindai secret yang dikembalikan
+// /api/admins/me/mfa/enroll sebelum 2FA benar-benar diaktifkan.
+type MFAConfirmRequest struct {
+	Code string `json:"code" binding:"required"`
+}
\ No newline at end of file
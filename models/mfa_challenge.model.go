@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MFAChallenge menampung tanda terima langkah pertama login untuk admin yang
+// mengaktifkan 2FA: Login menerbitkan mfa_token mentah ke klien tapi hanya
+// menyimpan hash SHA-256-nya di sini (serupa RefreshToken), sehingga token
+// yang bocor dari database saja tidak bisa dipakai menyelesaikan login.
+type MFAChallenge struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	TokenHash string             `json:"-" bson:"token_hash"`
+	AdminID   primitive.ObjectID `json:"admin_id" bson:"admin_id"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	ExpiresAt time.Time          `json:"expires_at" bson:"expires_at"`
+}
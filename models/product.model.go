@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProductImage mendefinisikan satu gambar dalam galeri produk, lengkap dengan
+// varian turunannya dari Cloudinary eager transformation.
+type ProductImage struct {
+	PublicID  string `json:"public_id" bson:"public_id"`
+	URL       string `json:"url" bson:"url"`
+	Width     int    `json:"width" bson:"width"`
+	Height    int    `json:"height" bson:"height"`
+	Thumbnail string `json:"thumbnail" bson:"thumbnail"`
+	IsPrimary bool   `json:"is_primary" bson:"is_primary"`
+}
+
+// Product mendefinisikan struktur untuk produk.
+type Product struct {
+	ID           primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Name         string             `json:"name" bson:"name"`
+	Price        float64            `json:"price" bson:"price"`
+	Description  string             `json:"description" bson:"description"`
+	Category     string             `json:"category" bson:"category"`
+	Images       []ProductImage     `json:"images" bson:"images"`
+	Stock        int                `json:"stock" bson:"stock"`
+	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at" bson:"updated_at"`
+	Version      int                `json:"version" bson:"version"`
+	ImagesBase64 []string           `json:"images_base64,omitempty" bson:"-"`
+}
+
+// Stats mendefinisikan struktur untuk statistik aplikasi.
+type Stats struct {
+	TotalProducts int64   `json:"total_products"`
+	TotalAdmins   int64   `json:"total_admins"`
+	TotalValue    float64 `json:"total_value"`
+}
\ No newline at end of file
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RefreshToken mendefinisikan struktur untuk refresh token yang diterbitkan
+// saat login. Hanya hash SHA-256 dari token mentah yang disimpan (bukan
+// nilai aslinya), sehingga database yang bocor tidak langsung membocorkan
+// token yang bisa dipakai ulang.
+type RefreshToken struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	TokenHash string             `json:"-" bson:"token_hash"`
+	AdminID   primitive.ObjectID `json:"admin_id" bson:"admin_id"`
+	UserAgent string             `json:"user_agent" bson:"user_agent"`
+	Revoked   bool               `json:"revoked" bson:"revoked"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	ExpiresAt time.Time          `json:"expires_at" bson:"expires_at"`
+}
+
+// RefreshTokenRequest mendefinisikan struktur untuk permintaan
+// POST /api/auth/refresh.
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest mendefinisikan struktur untuk permintaan POST /api/auth/logout.
+// All=true mencabut seluruh refresh token admin yang sedang login (logout
+// dari semua device), bukan hanya sesi yang sedang dipakai.
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+	All          bool   `json:"all"`
+}
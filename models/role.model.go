@@ -0,0 +1,10 @@
+package models
+
+// Role mendefinisikan struktur dokumen pada koleksi roles: nama role dan
+// daftar permission string yang dimilikinya. Role "superadmin" diperlakukan
+// khusus (wildcard, lihat middleware.TokenFooter.HasPermission) sehingga
+// permission-nya tidak perlu didaftarkan satu per satu.
+type Role struct {
+	Name        string   `json:"name" bson:"name"`
+	Permissions []string `json:"permissions" bson:"permissions"`
+}
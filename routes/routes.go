@@ -3,6 +3,10 @@ package routes
 import (
 	"net/http"
 	"pitipaw-backend/controllers"
+	"pitipaw-backend/internal/metrics"
+	"pitipaw-backend/internal/ratelimit"
+	"pitipaw-backend/middleware"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -13,35 +17,86 @@ func Setup(ctrl *controllers.Controller, env string) *gin.Engine {
 	if env == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
-	r := gin.Default()
+	r := gin.New()
+	r.Use(gin.Recovery())
+	r.Use(middleware.RequestID())
+	r.Use(middleware.StructuredLogging(ctrl.Logger))
+	r.Use(middleware.Metrics())
 
-	config := cors.DefaultConfig()
-	config.AllowOrigins = []string{"http://localhost:3000", "http://127.0.0.1:3000", "http://localhost:8000"}
-	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
-	r.Use(cors.New(config))
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
+	// AllowOriginFunc dievaluasi per-request, sehingga daftar origin yang
+	// diizinkan mengikuti AllowedOrigins di ctrl.Config saat itu juga --
+	// termasuk setelah hot-reload lewat SIGHUP atau POST /api/config/reload.
+	corsConfig.AllowOriginFunc = func(origin string) bool {
+		if ctrl.Config == nil {
+			return false
+		}
+		for _, allowed := range ctrl.Config.Current().AllowedOrigins {
+			if allowed == origin {
+				return true
+			}
+		}
+		return false
+	}
+	r.Use(cors.New(corsConfig))
+
+	auth := middleware.AuthMiddleware(ctrl.DB, ctrl.PasetoSecretKey)
+
+	// credentialLimiter menampung percobaan login/register (TTL 10 menit
+	// cukup untuk menutupi lockout eksponensial); uploadLimiter menampung
+	// kuota per-admin yang direset tiap jam, jadi TTL-nya disamakan.
+	credentialLimiter := ratelimit.NewMemoryStore(10 * time.Minute)
+	uploadLimiter := ratelimit.NewMemoryStore(time.Hour)
+	credentialRateLimit := middleware.CredentialRateLimit(credentialLimiter)
+	uploadRateLimit := middleware.UploadRateLimit(uploadLimiter)
+	passwordConfirm := middleware.RequirePasswordConfirmation(ctrl.DB)
 
 	api := r.Group("/api")
 	{
 		// Rute utilitas
 		api.GET("/health", ctrl.HealthCheck)
 		api.GET("/stats", ctrl.GetStats)
+		api.GET("/stats/by-category", ctrl.GetStatsByCategory)
+		api.GET("/stats/low-stock", ctrl.GetLowStockProducts)
+		api.GET("/stats/price-histogram", ctrl.GetPriceHistogram)
+		api.GET("/stats/timeseries", ctrl.GetTimeseries)
 
 		// Rute otentikasi
-		api.POST("/login", ctrl.Login)
-		api.POST("/register", ctrl.Register)
-		
+		api.POST("/login", credentialRateLimit, ctrl.Login)
+		api.POST("/login/verify", credentialRateLimit, ctrl.LoginVerify)
+		api.POST("/register", credentialRateLimit, ctrl.Register)
+		api.POST("/auth/refresh", ctrl.RefreshToken)
+		api.POST("/auth/logout", auth, ctrl.Logout)
+
 		// Rute produk
 		api.GET("/products", ctrl.GetProducts)
-		api.POST("/products", ctrl.CreateProduct)
+		api.POST("/products", auth, uploadRateLimit, middleware.Idempotency(ctrl.DB), ctrl.CreateProduct)
 		api.GET("/products/:id", ctrl.GetProduct)
-		api.PUT("/products/:id", ctrl.UpdateProduct)
-		api.DELETE("/products/:id", ctrl.DeleteProduct)
+		api.PUT("/products/:id", auth, uploadRateLimit, ctrl.UpdateProduct)
+		api.PATCH("/products/:id", auth, ctrl.PatchProduct)
+		api.DELETE("/products/:id", auth, ctrl.DeleteProduct)
 
 		// Rute admin
-		api.GET("/admins", ctrl.GetAdmins)
-		api.POST("/admins", ctrl.CreateAdmin) // Mungkin tidak perlu jika ada /register
-		api.DELETE("/admins/:id", ctrl.DeleteAdmin)
+		api.GET("/admins", auth, middleware.RequirePermission("admins:read"), ctrl.GetAdmins)
+		api.POST("/admins", auth, middleware.RequirePermission("admins:create"), ctrl.CreateAdmin) // Mungkin tidak perlu jika ada /register
+		api.PATCH("/admins/:id", auth, passwordConfirm, middleware.RequireSelfOrPermission("admins:update"), ctrl.UpdateAdmin)
+		api.PATCH("/admins/:id/password", auth, passwordConfirm, middleware.RequireSelfOrPermission("admins:update"), ctrl.ChangePassword)
+		api.DELETE("/admins/:id", auth, passwordConfirm, middleware.RequirePermission("admins:delete"), ctrl.DeleteAdmin)
+		api.POST("/admins/:id/unlock", auth, middleware.RequirePermission("admins:unlock"), ctrl.UnlockAdmin)
+		api.POST("/admins/me/mfa/enroll", auth, passwordConfirm, ctrl.EnrollMFA)
+		api.POST("/admins/me/mfa/confirm", auth, passwordConfirm, ctrl.ConfirmMFA)
+
+		// Rute admin internal
+		admin := api.Group("/admin")
+		{
+			admin.GET("/reconcile/status", auth, ctrl.GetReconcileStatus)
+			admin.GET("/config", auth, middleware.RequirePermission("config:manage"), ctrl.GetConfig)
+			admin.POST("/config/reload", auth, middleware.RequirePermission("config:manage"), ctrl.ReloadConfig)
+		}
 	}
 
 	r.NoRoute(func(c *gin.Context) {